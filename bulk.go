@@ -0,0 +1,269 @@
+package mopinion
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const defaultBulkWorkers = 4
+
+// BulkOptions configures how feedback is fetched in bulk.
+type BulkOptions struct {
+	// Limit is the number of feedback items per page. The API default is used if it is zero.
+	Limit int
+
+	// Sort and Order are passed along with every page, as in PaginationOptions.
+	Sort  string
+	Order string
+
+	// Workers is the number of pages fetched in parallel. It defaults to 4.
+	Workers int
+
+	// RequestsPerSecond limits how many pages are requested per second, if positive.
+	RequestsPerSecond float64
+}
+
+// FeedbackResult is delivered by the streaming bulk fetch, either with a feedback item
+// or with the error that stopped the fetch.
+type FeedbackResult struct {
+	Feedback FeedbackData
+	// Page is the page the feedback item comes from.
+	Page int
+	Err  error
+}
+
+type feedbackFetcher func(ctx context.Context, options *PaginationOptions) (*Feedback, *Response, error)
+
+// BulkByDataset returns all feedback of the given dataset in the original order. The first
+// page is fetched on its own to learn the total; the remaining pages are fetched in parallel.
+func (s *FeedbackService) BulkByDataset(ctx context.Context, datasetID int, options *BulkOptions, filters *FilterCollection) ([]FeedbackData, error) {
+	return bulkOrdered(ctx, options, func(ctx context.Context, options *PaginationOptions) (*Feedback, *Response, error) {
+		return s.GetByDataset(ctx, datasetID, options, filters)
+	})
+}
+
+// BulkByReport returns all feedback of the given report in the original order. The first
+// page is fetched on its own to learn the total; the remaining pages are fetched in parallel.
+func (s *FeedbackService) BulkByReport(ctx context.Context, reportID int, options *BulkOptions, filters *FilterCollection) ([]FeedbackData, error) {
+	return bulkOrdered(ctx, options, func(ctx context.Context, options *PaginationOptions) (*Feedback, *Response, error) {
+		return s.GetByReport(ctx, reportID, options, filters)
+	})
+}
+
+// StreamByDataset fetches all feedback of the given dataset like BulkByDataset, but delivers
+// every item as soon as its page arrives, so pages may be out of order. The channel is closed
+// when all pages are delivered or the fetch stops; an error is delivered as the last result.
+// The caller must drain the channel or cancel the context.
+func (s *FeedbackService) StreamByDataset(ctx context.Context, datasetID int, options *BulkOptions, filters *FilterCollection) <-chan FeedbackResult {
+	return bulkStream(ctx, options, func(ctx context.Context, options *PaginationOptions) (*Feedback, *Response, error) {
+		return s.GetByDataset(ctx, datasetID, options, filters)
+	})
+}
+
+// StreamByReport fetches all feedback of the given report like BulkByReport, but delivers
+// every item as soon as its page arrives, so pages may be out of order. The channel is closed
+// when all pages are delivered or the fetch stops; an error is delivered as the last result.
+// The caller must drain the channel or cancel the context.
+func (s *FeedbackService) StreamByReport(ctx context.Context, reportID int, options *BulkOptions, filters *FilterCollection) <-chan FeedbackResult {
+	return bulkStream(ctx, options, func(ctx context.Context, options *PaginationOptions) (*Feedback, *Response, error) {
+		return s.GetByReport(ctx, reportID, options, filters)
+	})
+}
+
+func bulkOrdered(ctx context.Context, options *BulkOptions, fetch feedbackFetcher) ([]FeedbackData, error) {
+	var (
+		mu    sync.Mutex
+		pages = map[int][]FeedbackData{}
+		last  int
+	)
+	err := bulkFetch(ctx, options, fetch, func(page int, data []FeedbackData) error {
+		mu.Lock()
+		defer mu.Unlock()
+		pages[page] = data
+		if page > last {
+			last = page
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var feedback []FeedbackData
+	for page := 1; page <= last; page++ {
+		feedback = append(feedback, pages[page]...)
+	}
+	return feedback, nil
+}
+
+func bulkStream(ctx context.Context, options *BulkOptions, fetch feedbackFetcher) <-chan FeedbackResult {
+	results := make(chan FeedbackResult)
+	go func() {
+		defer close(results)
+		err := bulkFetch(ctx, options, fetch, func(page int, data []FeedbackData) error {
+			for _, feedback := range data {
+				select {
+				case results <- FeedbackResult{Feedback: feedback, Page: page}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			select {
+			case results <- FeedbackResult{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return results
+}
+
+// bulkFetch fetches the first page, then the remaining pages with a pool of workers,
+// handing every page to deliver. The page size is taken from the first page, as the API
+// may return fewer items than the limit asked for. Without a usable total, or when the
+// last page still has more, pages are fetched one by one until there are no more.
+// It stops at the first error.
+func bulkFetch(ctx context.Context, options *BulkOptions, fetch feedbackFetcher, deliver func(page int, data []FeedbackData) error) error {
+	if options == nil {
+		options = &BulkOptions{}
+	}
+	pagination := func(page int) *PaginationOptions {
+		return &PaginationOptions{Page: page, Limit: options.Limit, Sort: options.Sort, Order: options.Order}
+	}
+
+	first, _, err := fetch(ctx, pagination(1))
+	if errors.Is(err, ErrPageOutOfRange) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := deliver(1, first.Data); err != nil {
+		return err
+	}
+	if !first.Meta.HasMore || len(first.Data) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var throttle <-chan time.Time
+	if options.RequestsPerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / options.RequestsPerSecond))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+	wait := func() error {
+		if throttle == nil {
+			return ctx.Err()
+		}
+		select {
+		case <-throttle:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// sequential fetches the pages from the given one until a page has no more.
+	sequential := func(page int) error {
+		for ; ; page++ {
+			if err := wait(); err != nil {
+				return err
+			}
+			feedback, _, err := fetch(ctx, pagination(page))
+			if errors.Is(err, ErrPageOutOfRange) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := deliver(page, feedback.Data); err != nil {
+				return err
+			}
+			if !feedback.Meta.HasMore || len(feedback.Data) == 0 {
+				return nil
+			}
+		}
+	}
+
+	pageSize := len(first.Data)
+	if first.Meta.Total <= pageSize {
+		return sequential(2)
+	}
+	pageCount := (first.Meta.Total + pageSize - 1) / pageSize
+	workers := options.Workers
+	if workers <= 0 {
+		workers = defaultBulkWorkers
+	}
+
+	pages := make(chan int)
+	go func() {
+		defer close(pages)
+		for page := 2; page <= pageCount; page++ {
+			select {
+			case pages <- page:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		// lastHasMore is set if the last page still has more, when the total grew.
+		lastHasMore bool
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range pages {
+				if err := wait(); err != nil {
+					return
+				}
+				feedback, _, err := fetch(ctx, pagination(page))
+				// The total may shrink while fetching; missing pages are simply empty.
+				if errors.Is(err, ErrPageOutOfRange) {
+					continue
+				}
+				if err != nil {
+					fail(err)
+					return
+				}
+				if err := deliver(page, feedback.Data); err != nil {
+					fail(err)
+					return
+				}
+				if page == pageCount {
+					lastHasMore = feedback.Meta.HasMore && len(feedback.Data) > 0
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if lastHasMore {
+		return sequential(pageCount + 1)
+	}
+	return nil
+}
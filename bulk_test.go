@@ -0,0 +1,209 @@
+package mopinion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func feedbackIDs(feedback []FeedbackData) []int {
+	ids := make([]int, len(feedback))
+	for i, f := range feedback {
+		ids[i] = f.ID
+	}
+	return ids
+}
+
+func sequence(n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	return ids
+}
+
+// withTotal reports the given total on every page instead of the real one, zero leaving it out.
+func withTotal(pages http.HandlerFunc, total int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		pages(rec, r)
+		var feedback Feedback
+		if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &feedback) != nil {
+			w.WriteHeader(rec.Code)
+			w.Write(rec.Body.Bytes())
+			return
+		}
+		feedback.Meta.Total = total
+		json.NewEncoder(w).Encode(feedback)
+	}
+}
+
+func TestBulkByReport(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/1/feedback", feedbackPages(t, 23, false))
+
+	feedback, err := client.Feedback.BulkByReport(context.Background(), 1, &BulkOptions{Limit: 5, Workers: 3}, filterCollection)
+	if err != nil {
+		t.Fatalf("bulk fetch should not return an error: %s", err)
+	}
+	if ids := feedbackIDs(feedback); !reflect.DeepEqual(sequence(23), ids) {
+		t.Errorf("expected feedback ids: %v but got: %v", sequence(23), ids)
+	}
+}
+
+func TestBulkByDatasetSinglePage(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	requests := 0
+	pages := feedbackPages(t, 3, false)
+	mux.HandleFunc("/datasets/2/feedback", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		pages(w, r)
+	})
+
+	feedback, err := client.Feedback.BulkByDataset(context.Background(), 2, &BulkOptions{Limit: 10}, filterCollection)
+	if err != nil {
+		t.Fatalf("bulk fetch should not return an error: %s", err)
+	}
+	if ids := feedbackIDs(feedback); !reflect.DeepEqual(sequence(3), ids) {
+		t.Errorf("expected feedback ids: %v but got: %v", sequence(3), ids)
+	}
+	if requests != 1 {
+		t.Errorf("expected %d request but got: %d", 1, requests)
+	}
+}
+
+func TestBulkError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	pages := feedbackPages(t, 30, false)
+	mux.HandleFunc("/reports/1/feedback", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "4" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"status": 403, "error_code": 10, "title": "You don't have enough rights for this action"}`)
+			return
+		}
+		pages(w, r)
+	})
+
+	feedback, err := client.Feedback.BulkByReport(context.Background(), 1, &BulkOptions{Limit: 5}, filterCollection)
+	if !errors.Is(err, ErrNotAuthorized) {
+		t.Errorf("bulk fetch should return ErrNotAuthorized but got: %v", err)
+	}
+	if feedback != nil {
+		t.Errorf("feedback should be nil but got: %v", feedback)
+	}
+}
+
+func TestStreamByReport(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/1/feedback", feedbackPages(t, 17, false))
+
+	var ids []int
+	for result := range client.Feedback.StreamByReport(context.Background(), 1, &BulkOptions{Limit: 4, Workers: 2}, filterCollection) {
+		if result.Err != nil {
+			t.Fatalf("stream should not return an error: %s", result.Err)
+		}
+		if expected := (result.Feedback.ID-1)/4 + 1; result.Page != expected {
+			t.Errorf("expected page: %v for feedback %v but got: %v", expected, result.Feedback.ID, result.Page)
+		}
+		ids = append(ids, result.Feedback.ID)
+	}
+	sort.Ints(ids)
+	if !reflect.DeepEqual(sequence(17), ids) {
+		t.Errorf("expected feedback ids: %v but got: %v", sequence(17), ids)
+	}
+}
+
+func TestStreamCancel(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/1/feedback", feedbackPages(t, 100, false))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := client.Feedback.StreamByReport(ctx, 1, &BulkOptions{Limit: 2}, filterCollection)
+	<-results
+	cancel()
+	// The channel is closed once the workers notice the cancellation.
+	for range results {
+	}
+}
+
+func TestBulkRateLimit(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/1/feedback", feedbackPages(t, 10, false))
+
+	start := time.Now()
+	options := &BulkOptions{Limit: 2, Workers: 5, RequestsPerSecond: 100}
+	if _, err := client.Feedback.BulkByReport(context.Background(), 1, options, filterCollection); err != nil {
+		t.Fatalf("bulk fetch should not return an error: %s", err)
+	}
+	// 4 pages after the first one, 10ms apart.
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("bulk fetch should be rate limited, but took %s", elapsed)
+	}
+}
+
+func TestBulkPageSize(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	// The API returns at most 3 items per page, whatever the limit.
+	pages := feedbackPages(t, 14, false)
+	mux.HandleFunc("/reports/1/feedback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		query.Set("limit", "3")
+		r.URL.RawQuery = query.Encode()
+		pages(w, r)
+	})
+
+	feedback, err := client.Feedback.BulkByReport(context.Background(), 1, &BulkOptions{Limit: 10}, filterCollection)
+	if err != nil {
+		t.Fatalf("bulk fetch should not return an error: %s", err)
+	}
+	if ids := feedbackIDs(feedback); !reflect.DeepEqual(sequence(14), ids) {
+		t.Errorf("expected feedback ids: %v but got: %v", sequence(14), ids)
+	}
+}
+
+func TestBulkTotal(t *testing.T) {
+	tests := []struct {
+		name  string
+		total int
+	}{
+		{"missing", 0},
+		{"stale", 9},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, mux, _, teardown := setup()
+			defer teardown()
+
+			mux.HandleFunc("/reports/1/feedback", withTotal(feedbackPages(t, 17, false), test.total))
+
+			feedback, err := client.Feedback.BulkByReport(context.Background(), 1, &BulkOptions{Limit: 4, Workers: 2}, filterCollection)
+			if err != nil {
+				t.Fatalf("bulk fetch should not return an error: %s", err)
+			}
+			if ids := feedbackIDs(feedback); !reflect.DeepEqual(sequence(17), ids) {
+				t.Errorf("expected feedback ids: %v but got: %v", sequence(17), ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Command mopinion-proxy is a local reverse proxy for the Mopinion API, for tools that
+// cannot sign requests themselves. It accepts unsigned requests, adds the x-auth-token
+// header and forwards them to the API. The proxy gets a token with its own keys and
+// gets a new one when the API rejects it.
+//
+// Keys are read from MOPINION_PUBLIC_KEY and MOPINION_PRIVATE_KEY, or else from a
+// credentials file. Only the routes allowed with -allow are forwarded, by default any
+// GET request:
+//
+//	mopinion-proxy -listen 127.0.0.1:8080 -allow "GET /account" -allow "GET,PUT /reports/**"
+//	curl http://127.0.0.1:8080/reports/1
+//
+// Every request is logged with its method, path, status, outcome and latency.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/oylmz/mopinion"
+)
+
+func main() {
+	var (
+		listen      = flag.String("listen", "127.0.0.1:8080", "address to listen on")
+		baseURL     = flag.String("base-url", "", "base url of the Mopinion API (default https://api.mopinion.com/)")
+		credentials = flag.String("credentials", "", "credentials file (default ~/.mopinion/credentials)")
+		profile     = flag.String("profile", "", "profile in the credentials file (default \"default\")")
+		timeout     = flag.Duration("timeout", 30*time.Second, "time limit for getting a token")
+		allow       rules
+	)
+	flag.Var(&allow, "allow", "allow requests matching `METHODS PATTERN`, e.g. \"GET,PUT /reports/*\"; may be repeated")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	if len(allow) == 0 {
+		allow.Set("GET /**")
+	}
+
+	options := []mopinion.Option{mopinion.WithTimeout(*timeout)}
+	if *baseURL != "" {
+		options = append(options, mopinion.WithBaseURL(*baseURL))
+	}
+	client, err := mopinion.NewClient(mopinion.NewChainCredentialProvider(
+		mopinion.NewEnvCredentialProvider(),
+		mopinion.NewFileCredentialProvider(*credentials, *profile)), options...)
+	if err != nil {
+		logger.Fatalf("create client: %s", err)
+	}
+
+	p := newProxy(client, allow, logger)
+	// Fail early if the keys are not accepted.
+	if _, err := p.currentToken(context.Background()); err != nil {
+		logger.Fatal(err)
+	}
+
+	server := &http.Server{Addr: *listen, Handler: p}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Printf("shut down: %s", err)
+		}
+	}()
+
+	logger.Printf("forwarding %s to %s, allowing %s", *listen, client.BaseURL, allow.String())
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		logger.Fatal(err)
+	}
+	<-done
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oylmz/mopinion"
+)
+
+// proxy forwards unsigned requests to the Mopinion API, signing them on the way.
+// Requests are only forwarded if the allowlist allows their method and path.
+type proxy struct {
+	client    *mopinion.Client
+	allow     rules
+	logger    *log.Logger
+	transport http.RoundTripper
+	reverse   *httputil.ReverseProxy
+
+	// token is the token the client signs requests with. It is guarded by mu, which
+	// is held while a new token is fetched so concurrent requests share one.
+	token *mopinion.Token
+	mu    sync.Mutex
+}
+
+func newProxy(client *mopinion.Client, allow rules, logger *log.Logger) *proxy {
+	p := &proxy{client: client, allow: allow, logger: logger, transport: http.DefaultTransport}
+	p.reverse = &httputil.ReverseProxy{
+		Director:     p.direct,
+		Transport:    roundTripperFunc(p.roundTrip),
+		ErrorHandler: p.fail,
+		ErrorLog:     logger,
+	}
+	return p
+}
+
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	// Clean the path first, so it can't sneak past the allowlist with dot segments.
+	r.URL.Path = "/" + strings.Join(splitPath(r.URL.Path), "/")
+	r.URL.RawPath = ""
+
+	rec := &recorder{ResponseWriter: w}
+	outcome := "forwarded"
+	switch {
+	case r.URL.Path == "/token":
+		outcome = "denied"
+		writeError(rec, http.StatusForbidden, "tokens are handled by the proxy")
+	case !p.allow.allows(r.Method, r.URL.Path):
+		outcome = "denied"
+		writeError(rec, http.StatusForbidden, "route is not allowed by the proxy")
+	default:
+		p.reverse.ServeHTTP(rec, r)
+		if rec.err != nil {
+			outcome = "failed"
+		}
+	}
+
+	status := rec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	line := fmt.Sprintf("method=%s path=%s status=%d outcome=%s latency=%s bytes=%d",
+		r.Method, r.URL.Path, status, outcome, time.Since(start), rec.bytes)
+	if rec.err != nil {
+		line += fmt.Sprintf(" error=%q", rec.err)
+	}
+	p.logger.Print(line)
+}
+
+// direct points the outgoing request at the API. Credentials sent by the caller are
+// dropped; the request is signed by roundTrip.
+func (p *proxy) direct(req *http.Request) {
+	target := *p.client.BaseURL
+	target.Path += strings.TrimPrefix(req.URL.Path, "/")
+	target.RawPath = ""
+	target.RawQuery = req.URL.RawQuery
+	req.URL = &target
+	req.Host = target.Host
+	req.Header.Del("Authorization")
+	req.Header.Del(mopinion.AuthTokenHeader)
+	if p.client.UserAgent != "" {
+		req.Header.Set("User-Agent", p.client.UserAgent)
+	}
+}
+
+// roundTrip signs the request and sends it. When the API rejects the token, a new
+// token is fetched and the request is signed and sent once more. The request is a
+// copy made by the reverse proxy, so it may be modified.
+func (p *proxy) roundTrip(req *http.Request) (*http.Response, error) {
+	token, err := p.currentToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.client.AddAutheticationToken(req); err != nil {
+		return nil, err
+	}
+	resp, err := p.transport.RoundTrip(req)
+	if err != nil || !isTokenError(resp) {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	if _, err := p.refreshToken(req.Context(), token); err != nil {
+		return nil, err
+	}
+	if req.GetBody != nil {
+		if req.Body, err = req.GetBody(); err != nil {
+			return nil, fmt.Errorf("rewind request body: %s", err)
+		}
+	}
+	if err := p.client.AddAutheticationToken(req); err != nil {
+		return nil, err
+	}
+	return p.transport.RoundTrip(req)
+}
+
+// currentToken returns the token requests are signed with, fetching one if there is none yet.
+func (p *proxy) currentToken(ctx context.Context) (*mopinion.Token, error) {
+	p.mu.Lock()
+	token := p.token
+	p.mu.Unlock()
+	if token != nil {
+		return token, nil
+	}
+	return p.refreshToken(ctx, nil)
+}
+
+// refreshToken fetches a new token to replace the stale one. If another request has
+// replaced the stale token in the meantime, its token is returned instead.
+func (p *proxy) refreshToken(ctx context.Context, stale *mopinion.Token) (*mopinion.Token, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != stale {
+		return p.token, nil
+	}
+	token, _, err := p.client.Token.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get token: %s", err)
+	}
+	p.token = token
+	p.logger.Print("token refreshed")
+	return token, nil
+}
+
+// fail answers requests which could not be forwarded.
+func (p *proxy) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if rec, ok := w.(*recorder); ok {
+		rec.err = err
+	}
+	writeError(w, http.StatusBadGateway, "request could not be forwarded to the API")
+}
+
+// isTokenError reports whether the API rejected the token of the request. The body of
+// the response is restored, so it can still be passed on.
+func isTokenError(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return false
+	}
+	err = mopinion.CheckResponse(&http.Response{StatusCode: resp.StatusCode, Body: ioutil.NopCloser(bytes.NewReader(data))})
+	return errors.Is(err, mopinion.ErrInvalidToken) || errors.Is(err, mopinion.ErrNotAuthenticated)
+}
+
+// writeError writes an error in the format of the API.
+func writeError(w http.ResponseWriter, status int, title string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": status, "title": title, "type": "proxy"})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// recorder keeps the status and size of a response, and the error forwarding failed with, for logging.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+	err    error
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/oylmz/mopinion"
+	"github.com/oylmz/mopinion/mopiniontest"
+)
+
+func setup(t *testing.T, allow ...string) (*mopiniontest.Server, *httptest.Server, *bytes.Buffer) {
+	api := mopiniontest.NewServer("publickey", "privatekey")
+	api.Seed(&mopiniontest.Fixtures{Reports: []mopinion.Report{{ID: 1, Name: "Website"}}})
+	client, err := api.NewClient()
+	if err != nil {
+		api.Close()
+		t.Fatalf("creating a client should not return an error: %s", err)
+	}
+
+	var rs rules
+	for _, rule := range allow {
+		if err := rs.Set(rule); err != nil {
+			api.Close()
+			t.Fatalf("parsing rule %q should not return an error: %s", rule, err)
+		}
+	}
+	logs := new(bytes.Buffer)
+	server := httptest.NewServer(newProxy(client, rs, log.New(logs, "", 0)))
+	return api, server, logs
+}
+
+func send(t *testing.T, method, url, body string) (int, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("creating a request should not return an error: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("sending the request should not return an error: %s", err)
+	}
+	defer resp.Body.Close()
+	data, _ := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestProxy(t *testing.T) {
+	api, server, logs := setup(t, "GET /**", "PUT /reports/*")
+	defer api.Close()
+	defer server.Close()
+
+	status, body := send(t, "GET", server.URL+"/reports/1", "")
+	if status != http.StatusOK || !strings.Contains(body, `"Website"`) {
+		t.Errorf("expected the report but got: %d %s", status, body)
+	}
+
+	// The body is signed as well.
+	status, body = send(t, "PUT", server.URL+"/reports/1", `{"name":"App"}`)
+	if status != http.StatusOK {
+		t.Errorf("expected the report to be updated but got: %d %s", status, body)
+	}
+
+	// A new token is fetched when the old one is rejected.
+	api.RevokeTokens()
+	status, body = send(t, "GET", server.URL+"/reports/1", "")
+	if status != http.StatusOK || !strings.Contains(body, `"App"`) {
+		t.Errorf("expected the updated report but got: %d %s", status, body)
+	}
+
+	for _, denied := range []struct{ method, path string }{
+		{"DELETE", "/reports/1"},
+		{"PUT", "/reports/1/../../account"},
+		{"GET", "/token"},
+	} {
+		status, body = send(t, denied.method, server.URL+denied.path, "")
+		var response map[string]interface{}
+		json.Unmarshal([]byte(body), &response)
+		if status != http.StatusForbidden || response["type"] != "proxy" {
+			t.Errorf("%s %s: expected the request to be denied but got: %d %s", denied.method, denied.path, status, body)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	expected := []string{
+		"token refreshed",
+		"method=GET path=/reports/1 status=200 outcome=forwarded",
+		"method=PUT path=/reports/1 status=200 outcome=forwarded",
+		"token refreshed",
+		"method=GET path=/reports/1 status=200 outcome=forwarded",
+		"method=DELETE path=/reports/1 status=403 outcome=denied",
+		"method=PUT path=/account status=403 outcome=denied",
+		"method=GET path=/token status=403 outcome=denied",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d log lines but got: %q", len(expected), lines)
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, expected[i]) {
+			t.Errorf("expected log line starting with: %q but got: %q", expected[i], line)
+		}
+	}
+}
+
+func TestProxyFailure(t *testing.T) {
+	api, server, logs := setup(t, "* /**")
+	defer server.Close()
+	api.Close()
+
+	if status, body := send(t, "GET", server.URL+"/account", ""); status != http.StatusBadGateway {
+		t.Errorf("expected status: %d but got: %d %s", http.StatusBadGateway, status, body)
+	}
+	if !strings.Contains(logs.String(), "outcome=failed") || !strings.Contains(logs.String(), "error=") {
+		t.Errorf("expected the failure to be logged but got: %s", logs)
+	}
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		rule    string
+		method  string
+		path    string
+		allowed bool
+	}{
+		{"GET /account", "GET", "/account", true},
+		{"GET /account", "POST", "/account", false},
+		{"GET /account", "GET", "/account/1", false},
+		{"get,put /reports/*", "PUT", "/reports/1", true},
+		{"GET /reports/*", "GET", "/reports", false},
+		{"GET /reports/*", "GET", "/reports/1/fields", false},
+		{"GET /reports/**", "GET", "/reports", true},
+		{"GET /reports/**", "GET", "/reports/1/feedback", true},
+		{"* /datasets/*/fields", "DELETE", "/datasets/2/fields", true},
+		{"* /datasets/*/fields", "DELETE", "/datasets/2/feedback", false},
+	}
+	for _, test := range tests {
+		r, err := parseRule(test.rule)
+		if err != nil {
+			t.Fatalf("parsing %q should not return an error: %s", test.rule, err)
+		}
+		if allowed := r.allows(test.method, test.path); allowed != test.allowed {
+			t.Errorf("%q: expected %s %s allowed: %t but got: %t", test.rule, test.method, test.path, test.allowed, allowed)
+		}
+	}
+
+	for _, invalid := range []string{"GET", "GET reports", "GET /**/fields", "GET /[", ", /account"} {
+		if _, err := parseRule(invalid); err == nil {
+			t.Errorf("parsing %q should return an error", invalid)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// rule allows requests with one of its methods to the paths matching its pattern.
+// Patterns are matched segment by segment: "*" matches a single segment and a
+// trailing "**" matches any number of segments, e.g. "/reports/*" or "/datasets/**".
+type rule struct {
+	methods  []string
+	segments []string
+}
+
+// parseRule parses a rule of the form "METHODS PATTERN", where METHODS is a comma
+// separated list of methods or "*" for any method, e.g. "GET,PUT /reports/*".
+func parseRule(s string) (rule, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return rule{}, fmt.Errorf("invalid rule %q: expected methods and a path pattern", s)
+	}
+	if !strings.HasPrefix(fields[1], "/") {
+		return rule{}, fmt.Errorf("invalid rule %q: path pattern must start with a slash", s)
+	}
+
+	var r rule
+	for _, method := range strings.Split(fields[0], ",") {
+		if method == "" {
+			return rule{}, fmt.Errorf("invalid rule %q: empty method", s)
+		}
+		r.methods = append(r.methods, strings.ToUpper(method))
+	}
+	r.segments = splitPath(fields[1])
+	for i, segment := range r.segments {
+		if segment == "**" && i != len(r.segments)-1 {
+			return rule{}, fmt.Errorf("invalid rule %q: ** is only allowed at the end", s)
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return rule{}, fmt.Errorf("invalid rule %q: %s", s, err)
+		}
+	}
+	return r, nil
+}
+
+// String returns the rule in the form it is parsed from.
+func (r rule) String() string {
+	return strings.Join(r.methods, ",") + " /" + strings.Join(r.segments, "/")
+}
+
+func (r rule) allows(method, urlPath string) bool {
+	if !r.allowsMethod(method) {
+		return false
+	}
+	segments := splitPath(urlPath)
+	for i, pattern := range r.segments {
+		if pattern == "**" {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if ok, _ := path.Match(pattern, segments[i]); !ok {
+			return false
+		}
+	}
+	return len(segments) == len(r.segments)
+}
+
+func (r rule) allowsMethod(method string) bool {
+	for _, m := range r.methods {
+		if m == "*" || m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// rules is an allowlist, which can be given as a repeated command line flag.
+type rules []rule
+
+// allows reports whether any of the rules allows the request.
+func (rs rules) allows(method, urlPath string) bool {
+	for _, r := range rs {
+		if r.allows(method, urlPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rs *rules) String() string {
+	if rs == nil {
+		return ""
+	}
+	s := make([]string, len(*rs))
+	for i, r := range *rs {
+		s[i] = r.String()
+	}
+	return strings.Join(s, "; ")
+}
+
+func (rs *rules) Set(value string) error {
+	r, err := parseRule(value)
+	if err != nil {
+		return err
+	}
+	*rs = append(*rs, r)
+	return nil
+}
+
+// splitPath returns the segments of a cleaned path, e.g. "reports", "1" for "/reports/1/".
+func splitPath(p string) []string {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/oylmz/mopinion"
+)
+
+// runFunc runs a command with its remaining arguments and returns the result to write.
+type runFunc func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error)
+
+// command is a subcommand. Setup registers the flags of the command and returns the
+// function running it, which reads the flags once they are parsed.
+type command struct {
+	args    string
+	summary string
+	setup   func(fs *flag.FlagSet) runFunc
+}
+
+// commands maps "<resource> <action>" to its command.
+var commands = map[string]command{
+	"account get": {"", "Get the account", func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			if len(args) != 0 {
+				return nil, errUsage
+			}
+			account, _, err := client.Account.Get(ctx)
+			return account, err
+		}
+	}},
+
+	"reports get": {"REPORT_ID", "Get a report", func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			id, err := parseID("report", args)
+			if err != nil {
+				return nil, err
+			}
+			report, _, err := client.Reports.Get(ctx, id)
+			return report, err
+		}
+	}},
+	"reports add": {"", "Add a report", func(fs *flag.FlagSet) runFunc {
+		report := reportFlags(fs)
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			if len(args) != 0 {
+				return nil, errUsage
+			}
+			added, _, err := client.Reports.Add(ctx, report)
+			return added, err
+		}
+	}},
+	"reports update": {"REPORT_ID", "Update the given fields of a report", func(fs *flag.FlagSet) runFunc {
+		report := reportFlags(fs)
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			var err error
+			if report.ID, err = parseID("report", args); err != nil {
+				return nil, err
+			}
+			updated, _, err := client.Reports.Update(ctx, report)
+			return updated, err
+		}
+	}},
+	"reports delete": {"REPORT_ID", "Delete a report and everything in it", func(fs *flag.FlagSet) runFunc {
+		dryRun := fs.Bool("dry-run", false, "only report what would be deleted")
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			id, err := parseID("report", args)
+			if err != nil {
+				return nil, err
+			}
+			deleted, _, err := client.Reports.Delete(ctx, id, *dryRun)
+			return deleted, err
+		}
+	}},
+
+	"datasets get": {"DATASET_ID", "Get a dataset", func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			id, err := parseID("dataset", args)
+			if err != nil {
+				return nil, err
+			}
+			dataset, _, err := client.Datasets.Get(ctx, id)
+			return dataset, err
+		}
+	}},
+	"datasets add": {"", "Add a dataset to a report", func(fs *flag.FlagSet) runFunc {
+		dataset := datasetFlags(fs)
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			if len(args) != 0 {
+				return nil, errUsage
+			}
+			added, _, err := client.Datasets.Add(ctx, dataset)
+			return added, err
+		}
+	}},
+	"datasets update": {"DATASET_ID", "Update the given fields of a dataset", func(fs *flag.FlagSet) runFunc {
+		dataset := datasetFlags(fs)
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			var err error
+			if dataset.ID, err = parseID("dataset", args); err != nil {
+				return nil, err
+			}
+			updated, _, err := client.Datasets.Update(ctx, dataset)
+			return updated, err
+		}
+	}},
+	"datasets delete": {"DATASET_ID", "Delete a dataset and its feedback", func(fs *flag.FlagSet) runFunc {
+		dryRun := fs.Bool("dry-run", false, "only report what would be deleted")
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			id, err := parseID("dataset", args)
+			if err != nil {
+				return nil, err
+			}
+			deleted, _, err := client.Datasets.Delete(ctx, id, *dryRun)
+			return deleted, err
+		}
+	}},
+
+	"deployments list": {"", "List the deployments", func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			if len(args) != 0 {
+				return nil, errUsage
+			}
+			deployments, _, err := client.Deployments.Get(ctx)
+			return deployments, err
+		}
+	}},
+	"deployments add": {"", "Add a deployment", func(fs *flag.FlagSet) runFunc {
+		deployment := new(mopinion.Deployment)
+		fs.StringVar(&deployment.Key, "key", "", "key of the deployment")
+		fs.StringVar(&deployment.Name, "name", "", "name of the deployment")
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			if len(args) != 0 {
+				return nil, errUsage
+			}
+			deployments, _, err := client.Deployments.Add(ctx, deployment)
+			return deployments, err
+		}
+	}},
+	"deployments delete": {"KEY", "Delete a deployment", func(fs *flag.FlagSet) runFunc {
+		dryRun := fs.Bool("dry-run", false, "only report what would be deleted")
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			if len(args) != 1 || args[0] == "" {
+				return nil, errUsage
+			}
+			deleted, _, err := client.Deployments.Delete(ctx, args[0], *dryRun)
+			return deleted, err
+		}
+	}},
+
+	"fields dataset": {"DATASET_ID", "List the fields of a dataset", func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			id, err := parseID("dataset", args)
+			if err != nil {
+				return nil, err
+			}
+			fields, _, err := client.Fields.GetByDataset(ctx, id)
+			return fields, err
+		}
+	}},
+	"fields report": {"REPORT_ID", "List the fields of a report", func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			id, err := parseID("report", args)
+			if err != nil {
+				return nil, err
+			}
+			fields, _, err := client.Fields.GetByReport(ctx, id)
+			return fields, err
+		}
+	}},
+
+	"feedback dataset": {"DATASET_ID", "List the feedback of a dataset", func(fs *flag.FlagSet) runFunc {
+		query := feedbackFlags(fs)
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			id, err := parseID("dataset", args)
+			if err != nil {
+				return nil, err
+			}
+			return query.run(ctx, id, client.Feedback.GetByDataset, client.Feedback.BulkByDataset)
+		}
+	}},
+	"feedback report": {"REPORT_ID", "List the feedback of a report", func(fs *flag.FlagSet) runFunc {
+		query := feedbackFlags(fs)
+		return func(ctx context.Context, client *mopinion.Client, args []string) (interface{}, error) {
+			id, err := parseID("report", args)
+			if err != nil {
+				return nil, err
+			}
+			return query.run(ctx, id, client.Feedback.GetByReport, client.Feedback.BulkByReport)
+		}
+	}},
+}
+
+func reportFlags(fs *flag.FlagSet) *mopinion.Report {
+	report := new(mopinion.Report)
+	fs.StringVar(&report.Name, "name", "", "name of the report")
+	fs.StringVar(&report.Description, "description", "", "description of the report")
+	fs.StringVar(&report.Language, "language", "", "language of the report, e.g. en_US")
+	return report
+}
+
+func datasetFlags(fs *flag.FlagSet) *mopinion.Dataset {
+	dataset := new(mopinion.Dataset)
+	fs.IntVar(&dataset.ReportID, "report", 0, "id of the report the dataset belongs to")
+	fs.StringVar(&dataset.Name, "name", "", "name of the dataset")
+	fs.StringVar(&dataset.Description, "description", "", "description of the dataset")
+	fs.StringVar(&dataset.DataSource, "data-source", "", "data source of the dataset")
+	return dataset
+}
+
+// feedbackQuery holds the pagination and filter flags of the feedback commands.
+type feedbackQuery struct {
+	options mopinion.PaginationOptions
+	filter  string
+	all     bool
+}
+
+func feedbackFlags(fs *flag.FlagSet) *feedbackQuery {
+	q := new(feedbackQuery)
+	fs.IntVar(&q.options.Page, "page", 0, "page to get, starting at 1")
+	fs.IntVar(&q.options.Limit, "limit", 0, "number of feedback items per page")
+	fs.StringVar(&q.options.Sort, "sort", "", "field to sort by, e.g. created")
+	fs.StringVar(&q.options.Order, "order", "", "sort order: asc or desc")
+	fs.StringVar(&q.filter, "filter", "", "filter expression, e.g. \"nps >= 9 and tags != spam\"")
+	fs.BoolVar(&q.all, "all", false, "get all pages instead of a single one")
+	return q
+}
+
+type (
+	pageFunc func(ctx context.Context, id int, options *mopinion.PaginationOptions, filters *mopinion.FilterCollection) (*mopinion.Feedback, *mopinion.Response, error)
+	bulkFunc func(ctx context.Context, id int, options *mopinion.BulkOptions, filters *mopinion.FilterCollection) ([]mopinion.FeedbackData, error)
+)
+
+// run gets a page of feedback, or all of it with -all.
+func (q *feedbackQuery) run(ctx context.Context, id int, page pageFunc, bulk bulkFunc) (interface{}, error) {
+	// Without an expression no filters are sent at all.
+	var filters *mopinion.FilterCollection
+	if q.filter != "" {
+		var err error
+		if filters, err = mopinion.ParseFilters(q.filter); err != nil {
+			return nil, err
+		}
+	}
+	if q.all {
+		if q.options.Page != 0 {
+			return nil, fmt.Errorf("%w: -page cannot be used with -all", errUsage)
+		}
+		options := &mopinion.BulkOptions{Limit: q.options.Limit, Sort: q.options.Sort, Order: q.options.Order}
+		return bulk(ctx, id, options, filters)
+	}
+	feedback, _, err := page(ctx, id, &q.options, filters)
+	return feedback, err
+}
+
+// parseID parses the only argument as the id of the named resource.
+func parseID(resource string, args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%w: expected a %s id", errUsage, resource)
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: invalid %s id %q", errUsage, resource, args[0])
+	}
+	return id, nil
+}
@@ -0,0 +1,155 @@
+// Command mopinion is a command line client for the Mopinion API.
+//
+// Usage:
+//
+//	mopinion <resource> <action> [flags] [arguments]
+//
+// For example:
+//
+//	mopinion account get
+//	mopinion reports add -name Website -language en_US
+//	mopinion datasets delete --dry-run 2
+//	mopinion feedback report -limit 50 -filter "nps >= 9 and date >= 2019-10-01" 1
+//	mopinion fields dataset -o yaml 2
+//
+// Results are written as a table, or as JSON or YAML with -o json or -o yaml. Keys are
+// read from MOPINION_PUBLIC_KEY and MOPINION_PRIVATE_KEY, or else from a credentials file.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oylmz/mopinion"
+)
+
+// Exit codes.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	c := &cli{stdout: os.Stdout, stderr: os.Stderr, newClient: newClient}
+	os.Exit(c.run(context.Background(), os.Args[1:]))
+}
+
+// settings holds the flags every command accepts.
+type settings struct {
+	format      string
+	baseURL     string
+	credentials string
+	profile     string
+	timeout     time.Duration
+}
+
+func (s *settings) register(fs *flag.FlagSet) {
+	fs.StringVar(&s.format, "o", formatTable, "output format: table, json or yaml")
+	fs.StringVar(&s.baseURL, "base-url", "", "base url of the Mopinion API (default https://api.mopinion.com/)")
+	fs.StringVar(&s.credentials, "credentials", "", "credentials file (default ~/.mopinion/credentials)")
+	fs.StringVar(&s.profile, "profile", "", "profile in the credentials file (default \"default\")")
+	fs.DurationVar(&s.timeout, "timeout", 30*time.Second, "time limit for every request")
+}
+
+func newClient(s *settings) (*mopinion.Client, error) {
+	options := []mopinion.Option{
+		mopinion.WithTimeout(s.timeout),
+		mopinion.WithRetryPolicy(mopinion.NewBackoffRetryPolicy()),
+		mopinion.WithUserAgent("mopinion-cli"),
+	}
+	if s.baseURL != "" {
+		options = append(options, mopinion.WithBaseURL(s.baseURL))
+	}
+	return mopinion.NewClient(mopinion.NewChainCredentialProvider(
+		mopinion.NewEnvCredentialProvider(),
+		mopinion.NewFileCredentialProvider(s.credentials, s.profile)), options...)
+}
+
+// cli runs commands, writing results to stdout and errors to stderr.
+type cli struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	newClient func(s *settings) (*mopinion.Client, error)
+}
+
+// errUsage is returned by commands called with the wrong arguments.
+var errUsage = errors.New("wrong arguments")
+
+// run runs the command given by the arguments and returns the exit code.
+func (c *cli) run(ctx context.Context, args []string) int {
+	if len(args) < 2 {
+		c.usage()
+		return exitUsage
+	}
+	name := args[0] + " " + args[1]
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(c.stderr, "mopinion: unknown command %q\n\n", name)
+		c.usage()
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("mopinion "+name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "%s\n\nUsage: mopinion %s [flags] %s\n\nFlags:\n", cmd.summary, name, cmd.args)
+		fs.PrintDefaults()
+	}
+	s := new(settings)
+	s.register(fs)
+	run := cmd.setup(fs)
+	if err := fs.Parse(args[2:]); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if s.format != formatTable && s.format != formatJSON && s.format != formatYAML {
+		fmt.Fprintf(c.stderr, "mopinion: unknown output format %q\n", s.format)
+		return exitUsage
+	}
+
+	client, err := c.newClient(s)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "mopinion: %s\n", err)
+		return exitError
+	}
+	result, err := run(ctx, client, fs.Args())
+	if errors.Is(err, errUsage) {
+		fmt.Fprintf(c.stderr, "mopinion: %s\n\n", err)
+		fs.Usage()
+		return exitUsage
+	}
+	if err != nil {
+		fmt.Fprintf(c.stderr, "mopinion: %s\n", err)
+		return exitError
+	}
+	if err := write(c.stdout, s.format, result); err != nil {
+		fmt.Fprintf(c.stderr, "mopinion: write output: %s\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+func (c *cli) usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprint(c.stderr, "Usage: mopinion <resource> <action> [flags] [arguments]\n\nCommands:\n")
+	for _, name := range names {
+		cmd := commands[name]
+		fmt.Fprintf(c.stderr, "  %-34s %s\n", strings.TrimSpace(name+" "+cmd.args), cmd.summary)
+	}
+	fmt.Fprint(c.stderr, "\nRun mopinion <resource> <action> -h for the flags of a command.\n")
+}
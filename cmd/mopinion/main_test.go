@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/oylmz/mopinion"
+	"github.com/oylmz/mopinion/mopiniontest"
+)
+
+func setup(t *testing.T) (*mopiniontest.Server, func(args ...string) (int, string, string)) {
+	server := mopiniontest.NewServer("publickey", "privatekey")
+	fixtures, err := mopiniontest.LoadFixtures("../../mopiniontest/testdata/fixtures.json")
+	if err != nil {
+		server.Close()
+		t.Fatalf("loading fixtures should not return an error: %s", err)
+	}
+	server.Seed(fixtures)
+
+	run := func(args ...string) (int, string, string) {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		c := &cli{stdout: stdout, stderr: stderr, newClient: func(*settings) (*mopinion.Client, error) {
+			return server.NewClient()
+		}}
+		code := c.run(context.Background(), args)
+		return code, stdout.String(), stderr.String()
+	}
+	return server, run
+}
+
+func TestCommands(t *testing.T) {
+	server, run := setup(t)
+	defer server.Close()
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"account", "get"}, "NAME  PACKAGE     END DATE  USERS  CHARTS  FORMS  REPORTS\nAcme  enterprise            0      0       0      1\n"},
+		{[]string{"reports", "get", "1"}, "ID  NAME     LANGUAGE  CREATED     DATASETS  DESCRIPTION\n1   Website  en_US     2019-05-02  1         \n"},
+		{[]string{"reports", "update", "-description", "Main site", "1"}, "ID  NAME     LANGUAGE  CREATED     DATASETS  DESCRIPTION\n1   Website  en_US     2019-05-02  1         Main site\n"},
+		{[]string{"datasets", "delete", "--dry-run", "2"}, "EXECUTED  RESOURCES AFFECTED\nfalse     datasets=1, feedback=5, fields=3\n"},
+		{[]string{"deployments", "list"}, "KEY          NAME\nab25of859d3  Default implementation\n"},
+		{[]string{"fields", "report", "1"}, "KEY    TYPE  LABEL                                    SHORT LABEL  REPORT  DATASET\n" +
+			"nps.1  nps   Would you recommend us?                  nps          1       2\n" +
+			"ces.1  ces   How easy was it?                         ces          1       2\n" +
+			"gcr.1  gcr   Did you find what you were looking for?  gcr          1       2\n"},
+		{[]string{"feedback", "report", "-filter", "nps >= 9 and tags != spam", "-order", "desc", "-limit", "2", "1"}, "ID  CREATED              REPORT  DATASET  TAGS  FIELDS\n" +
+			"5   2019-10-04 14:00:00  1       2              nps.1=10 ces.1=3 gcr.1=yes\n" +
+			"4   2019-10-03 13:00:00  1       2        vip   nps.1=9 ces.1=4 gcr.1=partly\n" +
+			"2 of 3 feedback items, use -page for more or -all for everything\n"},
+	}
+	for _, test := range tests {
+		code, stdout, stderr := run(test.args...)
+		if code != exitOK {
+			t.Errorf("%v: expected exit code 0 but got: %d, %s", test.args, code, stderr)
+			continue
+		}
+		if stdout != test.expected {
+			t.Errorf("%v: expected output:\n%s\nbut got:\n%s", test.args, test.expected, stdout)
+		}
+	}
+}
+
+func TestFeedbackQueryFilters(t *testing.T) {
+	var got []*mopinion.FilterCollection
+	page := func(ctx context.Context, id int, options *mopinion.PaginationOptions, filters *mopinion.FilterCollection) (*mopinion.Feedback, *mopinion.Response, error) {
+		got = append(got, filters)
+		return &mopinion.Feedback{}, nil, nil
+	}
+
+	q := &feedbackQuery{}
+	if _, err := q.run(context.Background(), 1, page, nil); err != nil {
+		t.Fatalf("running the query should not return an error: %s", err)
+	}
+	q.filter = "nps >= 9"
+	if _, err := q.run(context.Background(), 1, page, nil); err != nil {
+		t.Fatalf("running the query should not return an error: %s", err)
+	}
+	if got[0] != nil {
+		t.Errorf("expected no filters without -filter but got: %v", got[0])
+	}
+	if got[1] == nil || len(got[1].Filters) != 1 {
+		t.Errorf("expected 1 filter with -filter but got: %v", got[1])
+	}
+}
+
+func TestFormats(t *testing.T) {
+	server, run := setup(t)
+	defer server.Close()
+
+	code, stdout, stderr := run("reports", "add", "-name", "App", "-language", "nl_NL", "-o", "json")
+	if code != exitOK {
+		t.Fatalf("expected exit code 0 but got: %d, %s", code, stderr)
+	}
+	var report mopinion.Report
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil || report.ID == 0 || report.Name != "App" {
+		t.Errorf("expected the new report as JSON but got: %s, %v", stdout, err)
+	}
+
+	code, stdout, stderr = run("deployments", "add", "-key", "dpg93g038fm", "-name", "Staging: EU", "-o", "yaml")
+	if code != exitOK {
+		t.Fatalf("expected exit code 0 but got: %d, %s", code, stderr)
+	}
+	expected := `_meta:
+  Code: 200
+  Count: 1
+  has_more: false
+  Message: OK
+  Next: false
+  Previous: false
+  Total: 1
+Deployments:
+  - Key: dpg93g038fm
+    Name: "Staging: EU"
+`
+	if stdout != expected {
+		t.Errorf("expected output:\n%s\nbut got:\n%s", expected, stdout)
+	}
+
+	code, stdout, _ = run("feedback", "dataset", "-all", "-limit", "2", "-o", "json", "2")
+	var feedback []mopinion.FeedbackData
+	if err := json.Unmarshal([]byte(stdout), &feedback); code != exitOK || err != nil || len(feedback) != 5 {
+		t.Errorf("expected all feedback as JSON but got: %d, %s", code, stdout)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	server, run := setup(t)
+	defer server.Close()
+
+	tests := []struct {
+		args   []string
+		code   int
+		stderr string
+	}{
+		{nil, exitUsage, "Usage: mopinion <resource> <action>"},
+		{[]string{"reports", "list"}, exitUsage, `unknown command "reports list"`},
+		{[]string{"reports", "get"}, exitUsage, "expected a report id"},
+		{[]string{"reports", "get", "one"}, exitUsage, `invalid report id "one"`},
+		{[]string{"reports", "get", "-o", "xml", "1"}, exitUsage, `unknown output format "xml"`},
+		{[]string{"reports", "get", "-unknown", "1"}, exitUsage, "flag provided but not defined"},
+		{[]string{"feedback", "report", "-all", "-page", "2", "1"}, exitUsage, "-page cannot be used with -all"},
+		{[]string{"feedback", "report", "-filter", "nps >", "1"}, exitError, "invalid filter expression"},
+		{[]string{"reports", "get", "99"}, exitError, "title:Report not found"},
+		{[]string{"reports", "add"}, exitError, "report name cannot be empty"},
+	}
+	for _, test := range tests {
+		code, _, stderr := run(test.args...)
+		if code != test.code || !strings.Contains(stderr, test.stderr) {
+			t.Errorf("%v: expected exit code %d and %q but got: %d, %s", test.args, test.code, test.stderr, code, stderr)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/oylmz/mopinion"
+)
+
+// Output formats.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// write writes the result of a command in the given format.
+func write(w io.Writer, format string, v interface{}) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatYAML:
+		return writeYAML(w, v)
+	default:
+		return writeTable(w, v)
+	}
+}
+
+// table is a result laid out in rows, with an optional line below it.
+type table struct {
+	header []string
+	rows   [][]string
+	footer string
+}
+
+func (t *table) add(row ...interface{}) {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		cells[i] = fmt.Sprint(cell)
+	}
+	t.rows = append(t.rows, cells)
+}
+
+func writeTable(w io.Writer, v interface{}) error {
+	t, err := tableOf(v)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if t.footer != "" {
+		_, err = fmt.Fprintln(w, t.footer)
+	}
+	return err
+}
+
+// tableOf lays out the results of the commands in rows.
+func tableOf(v interface{}) (*table, error) {
+	switch v := v.(type) {
+	case *mopinion.Account:
+		t := &table{header: []string{"NAME", "PACKAGE", "END DATE", "USERS", "CHARTS", "FORMS", "REPORTS"}}
+		t.add(v.Name, v.Package, v.EndDate, v.NumberUsers, v.NumberCharts, v.NumberForms, v.NumberReports)
+		return t, nil
+	case *mopinion.Report:
+		t := &table{header: []string{"ID", "NAME", "LANGUAGE", "CREATED", "DATASETS", "DESCRIPTION"}}
+		created := ""
+		if v.Created != nil {
+			created = v.Created.String()
+		}
+		t.add(v.ID, v.Name, v.Language, created, len(v.Datasets), v.Description)
+		return t, nil
+	case *mopinion.Dataset:
+		t := &table{header: []string{"ID", "NAME", "REPORT", "DATA SOURCE", "DESCRIPTION"}}
+		t.add(v.ID, v.Name, v.ReportID, v.DataSource, v.Description)
+		return t, nil
+	case *mopinion.Deployments:
+		t := &table{header: []string{"KEY", "NAME"}}
+		for _, d := range v.Deployments {
+			t.add(d.Key, d.Name)
+		}
+		return t, nil
+	case *mopinion.Fields:
+		t := &table{header: []string{"KEY", "TYPE", "LABEL", "SHORT LABEL", "REPORT", "DATASET"}}
+		for _, f := range v.Data {
+			t.add(f.Key, f.Type, f.Label, f.ShortLabel, f.ReportID, f.DatasetID)
+		}
+		return t, nil
+	case *mopinion.Feedback:
+		t := feedbackTable(v.Data)
+		if v.Meta.HasMore {
+			t.footer = fmt.Sprintf("%d of %d feedback items, use -page for more or -all for everything", len(v.Data), v.Meta.Total)
+		}
+		return t, nil
+	case []mopinion.FeedbackData:
+		return feedbackTable(v), nil
+	case *mopinion.DeleteResponse:
+		t := &table{header: []string{"EXECUTED", "RESOURCES AFFECTED"}}
+		keys := make([]string, 0, len(v.ResourcesAffected))
+		for key := range v.ResourcesAffected {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		affected := make([]string, len(keys))
+		for i, key := range keys {
+			affected[i] = fmt.Sprintf("%s=%v", key, v.ResourcesAffected[key])
+		}
+		t.add(v.Executed, strings.Join(affected, ", "))
+		return t, nil
+	}
+	return nil, fmt.Errorf("no table layout for %T, use -o json or -o yaml", v)
+}
+
+func feedbackTable(feedback []mopinion.FeedbackData) *table {
+	t := &table{header: []string{"ID", "CREATED", "REPORT", "DATASET", "TAGS", "FIELDS"}}
+	for _, f := range feedback {
+		fields := make([]string, len(f.Fields))
+		for i, field := range f.Fields {
+			fields[i] = field.Key + "=" + formatValue(field.Value)
+		}
+		t.add(f.ID, f.Created, f.ReportID, f.DatasetID, strings.Join(f.Tags, ","), strings.Join(fields, " "))
+	}
+	return t
+}
+
+// formatValue formats the value of a feedback field for a table cell, quoting text
+// containing spaces so the fields can be told apart.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if v == "" || strings.ContainsAny(v, " \t\n\"") {
+			return strconv.Quote(v)
+		}
+		return v
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, value := range v {
+			values[i] = formatValue(value)
+		}
+		return strings.Join(values, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// writeYAML writes v as YAML. The value is encoded to JSON first, so it follows the same
+// field names and order, and then written in block style. The output is valid YAML 1.2;
+// strings are quoted where a YAML 1.1 parser could read them differently.
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	n, err := decodeNode(dec)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	n.writeYAML(&buf, 0)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// node is a JSON value which keeps the order of object keys.
+type node struct {
+	object bool
+	array  bool
+	keys   []string // keys of an object
+	values []*node  // values of an object, or elements of an array
+	scalar interface{}
+}
+
+// decodeNode reads the next value from the decoder.
+func decodeNode(dec *json.Decoder) (*node, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		n := &node{object: true}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.keys = append(n.keys, key.(string))
+			n.values = append(n.values, value)
+		}
+		_, err := dec.Token()
+		return n, err
+	case json.Delim('['):
+		n := &node{array: true}
+		for dec.More() {
+			value, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.values = append(n.values, value)
+		}
+		_, err := dec.Token()
+		return n, err
+	}
+	return &node{scalar: token}, nil
+}
+
+// block reports whether the node is written on lines of its own.
+func (n *node) block() bool {
+	return (n.object || n.array) && len(n.values) > 0
+}
+
+func (n *node) writeYAML(buf *bytes.Buffer, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch {
+	case n.object && n.block():
+		for i, key := range n.keys {
+			value := n.values[i]
+			buf.WriteString(pad + yamlString(key) + ":")
+			if value.block() {
+				buf.WriteString("\n")
+				value.writeYAML(buf, indent+2)
+			} else {
+				buf.WriteString(" " + value.inline() + "\n")
+			}
+		}
+	case n.array && n.block():
+		for _, value := range n.values {
+			if !value.block() {
+				buf.WriteString(pad + "- " + value.inline() + "\n")
+				continue
+			}
+			// The first line of the item goes after the dash.
+			var item bytes.Buffer
+			value.writeYAML(&item, indent+2)
+			buf.WriteString(pad + "- ")
+			buf.Write(item.Bytes()[indent+2:])
+		}
+	default:
+		buf.WriteString(pad + n.inline() + "\n")
+	}
+}
+
+// inline returns the node written on a single line.
+func (n *node) inline() string {
+	switch v := n.scalar.(type) {
+	case string:
+		return yamlString(v)
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	switch {
+	case n.object:
+		return "{}"
+	case n.array:
+		return "[]"
+	}
+	return "null"
+}
+
+// plainString matches strings which can be written without quotes.
+var plainString = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9_ ./@()-]*$`)
+
+// yamlString writes a string plain if it can't be mistaken for anything else, and double
+// quoted otherwise. Go escapes are valid in YAML double quoted strings.
+func yamlString(s string) string {
+	if plainString.MatchString(s) && !strings.HasSuffix(s, " ") && !yamlKeyword(s) {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+// yamlKeyword reports whether a plain string would be read as a boolean or null.
+func yamlKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "y", "n", "yes", "no", "true", "false", "on", "off", "null":
+		return true
+	}
+	return false
+}
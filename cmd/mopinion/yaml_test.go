@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteYAML(t *testing.T) {
+	v := map[string]interface{}{
+		"empty":   map[string]interface{}{},
+		"list":    []interface{}{1, "two", []interface{}{3, 4}, map[string]interface{}{"a": nil, "b": []interface{}{}}},
+		"strings": []string{"plain text", "", "yes", "No", "10", "2019-10-01", "a: b", "trailing ", "line\nbreak", "#hash"},
+	}
+	expected := `empty: {}
+list:
+  - 1
+  - two
+  - - 3
+    - 4
+  - a: null
+    b: []
+strings:
+  - plain text
+  - ""
+  - "yes"
+  - "No"
+  - "10"
+  - "2019-10-01"
+  - "a: b"
+  - "trailing "
+  - "line\nbreak"
+  - "#hash"
+`
+	var buf bytes.Buffer
+	if err := writeYAML(&buf, v); err != nil {
+		t.Fatalf("writing YAML should not return an error: %s", err)
+	}
+	if buf.String() != expected {
+		t.Errorf("expected YAML:\n%s\nbut got:\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,151 @@
+package mopinion
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyServer accepts requests signed with its current token only.
+type concurrencyServer struct {
+	*httptest.Server
+	client     *Client
+	tokenCalls int32
+
+	mu    sync.Mutex
+	token string
+}
+
+func newConcurrencyServer(t *testing.T) *concurrencyServer {
+	s := &concurrencyServer{token: "token1"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		calls := atomic.AddInt32(&s.tokenCalls, 1)
+		// Keep the token request in flight long enough for the others to pile up.
+		time.Sleep(20 * time.Millisecond)
+		s.mu.Lock()
+		s.token = fmt.Sprintf("token%d", calls)
+		token := s.token
+		s.mu.Unlock()
+		fmt.Fprintf(w, `{"token":%q}`, token)
+	})
+	routes := map[string]string{
+		"/account":             `{"name": "account name"}`,
+		"/deployments":         `{"0": {"key": "ab25of859d3", "name": "deployment 1"}}`,
+		"/reports/1":           `{"id": 1, "name": "report name"}`,
+		"/datasets/1":          `{"id": 1, "name": "dataset name", "report_id": 1}`,
+		"/datasets/1/fields":   `{"data": [{"key": "nps", "label": "NPS"}]}`,
+		"/reports/1/fields":    `{"data": [{"key": "nps", "label": "NPS"}]}`,
+		"/datasets/1/feedback": `{"data": [{"id": 1}], "_meta": {"has_more": false}}`,
+		"/reports/1/feedback":  `{"data": [{"id": 1}], "_meta": {"has_more": false}}`,
+	}
+	for route, body := range routes {
+		body := body
+		mux.HandleFunc(route, func(w http.ResponseWriter, r *http.Request) {
+			payload, _ := ioutil.ReadAll(r.Body)
+			s.mu.Lock()
+			expected := s.client.makeToken(&Token{Token: s.token}, r.URL.Path, payload)
+			s.mu.Unlock()
+			if r.Header.Get("x-auth-token") != expected {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"status": 401, "error_code": 4, "title": "Invalid token"}`)
+				return
+			}
+			fmt.Fprint(w, body)
+		})
+	}
+	s.Server = httptest.NewServer(mux)
+
+	client, err := NewClient(NewBasicCredentialProvider("publickey", "privatekey"), WithBaseURL(s.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient should not return an error: %s", err)
+	}
+	s.client = client
+	return s
+}
+
+func (s *concurrencyServer) hammer(t *testing.T, goroutines int) {
+	calls := []func(ctx context.Context) error{
+		func(ctx context.Context) error { _, _, err := s.client.Account.Get(ctx); return err },
+		func(ctx context.Context) error { _, _, err := s.client.Deployments.Get(ctx); return err },
+		func(ctx context.Context) error { _, _, err := s.client.Reports.Get(ctx, 1); return err },
+		func(ctx context.Context) error { _, _, err := s.client.Datasets.Get(ctx, 1); return err },
+		func(ctx context.Context) error { _, _, err := s.client.Fields.GetByDataset(ctx, 1); return err },
+		func(ctx context.Context) error { _, _, err := s.client.Fields.GetByReport(ctx, 1); return err },
+		func(ctx context.Context) error {
+			_, _, err := s.client.Feedback.GetByDataset(ctx, 1, paginationOptions, filterCollection)
+			return err
+		},
+		func(ctx context.Context) error {
+			_, _, err := s.client.Feedback.GetByReport(ctx, 1, paginationOptions, filterCollection)
+			return err
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(call func(ctx context.Context) error) {
+			defer wg.Done()
+			if err := call(context.Background()); err != nil {
+				t.Errorf("API call should not return an error: %s", err)
+			}
+		}(calls[i%len(calls)])
+	}
+	wg.Wait()
+}
+
+func TestConcurrentServices(t *testing.T) {
+	s := newConcurrencyServer(t)
+	defer s.Close()
+
+	s.hammer(t, 64)
+
+	if calls := atomic.LoadInt32(&s.tokenCalls); calls != 1 {
+		t.Errorf("expected token API to be called %d time but got: %d", 1, calls)
+	}
+}
+
+func TestConcurrentTokenRefresh(t *testing.T) {
+	s := newConcurrencyServer(t)
+	defer s.Close()
+
+	// The client starts with a token the server does not accept anymore.
+	s.client.SetToken(&Token{Token: "expired"})
+	s.hammer(t, 64)
+
+	if calls := atomic.LoadInt32(&s.tokenCalls); calls != 1 {
+		t.Errorf("expected token API to be called %d time but got: %d", 1, calls)
+	}
+	if token := s.client.getToken(); token.Token != "token1" {
+		t.Errorf("expected token: %v but got: %v", "token1", token.Token)
+	}
+}
+
+func TestConcurrentTokenRefreshCanceled(t *testing.T) {
+	s := newConcurrencyServer(t)
+	defer s.Close()
+
+	// The first caller gives up while the token request is in flight.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, _, err := s.client.Account.Get(ctx); err == nil {
+			t.Errorf("account API should return an error when the context expires")
+		}
+	}()
+	time.Sleep(time.Millisecond)
+	if _, _, err := s.client.Account.Get(context.Background()); err != nil {
+		t.Errorf("account API should not return an error: %s", err)
+	}
+	wg.Wait()
+}
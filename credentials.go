@@ -0,0 +1,266 @@
+package mopinion
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// PublicKeyEnv is the environment variable EnvCredentialProvider reads the public key from.
+	PublicKeyEnv = "MOPINION_PUBLIC_KEY"
+	// PrivateKeyEnv is the environment variable EnvCredentialProvider reads the private key from.
+	PrivateKeyEnv = "MOPINION_PRIVATE_KEY"
+
+	defaultProfile = "default"
+)
+
+// EnvCredentialProvider implements CredentialProvider by reading the keys from environment variables.
+type EnvCredentialProvider struct {
+	publicKeyEnv  string
+	privateKeyEnv string
+}
+
+// NewEnvCredentialProvider returns an EnvCredentialProvider reading
+// MOPINION_PUBLIC_KEY and MOPINION_PRIVATE_KEY.
+func NewEnvCredentialProvider() CredentialProvider {
+	return &EnvCredentialProvider{publicKeyEnv: PublicKeyEnv, privateKeyEnv: PrivateKeyEnv}
+}
+
+// Keys returns public and private keys respectively.
+func (e *EnvCredentialProvider) Keys() (string, string, error) {
+	publicKey, privateKey := os.Getenv(e.publicKeyEnv), os.Getenv(e.privateKeyEnv)
+	if publicKey == "" || privateKey == "" {
+		return "", "", fmt.Errorf("environment variables %s and %s must be set", e.publicKeyEnv, e.privateKeyEnv)
+	}
+	return publicKey, privateKey, nil
+}
+
+// FileCredentialProvider implements CredentialProvider by reading the keys of a named
+// profile from a file. The file is read on every call, so changes to it are picked up.
+//
+// The file is either JSON:
+//
+//	{"default": {"public_key": "...", "private_key": "..."}}
+//
+// or INI:
+//
+//	[default]
+//	public_key = ...
+//	private_key = ...
+//
+// Files readable by everyone are refused, since they expose the private key.
+type FileCredentialProvider struct {
+	path    string
+	profile string
+}
+
+// NewFileCredentialProvider returns a FileCredentialProvider for the given file and profile.
+// An empty path stands for DefaultCredentialsFile and an empty profile for "default".
+func NewFileCredentialProvider(path, profile string) CredentialProvider {
+	if profile == "" {
+		profile = defaultProfile
+	}
+	return &FileCredentialProvider{path: path, profile: profile}
+}
+
+// DefaultCredentialsFile returns the path of the credentials file in the home directory,
+// i.e. ~/.mopinion/credentials.
+func DefaultCredentialsFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".mopinion", "credentials"), nil
+}
+
+// Keys returns public and private keys respectively.
+func (f *FileCredentialProvider) Keys() (string, string, error) {
+	path := f.path
+	if path == "" {
+		var err error
+		if path, err = DefaultCredentialsFile(); err != nil {
+			return "", "", fmt.Errorf("find credentials file: %s", err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", "", err
+	}
+	if info.Mode().Perm()&0004 != 0 {
+		return "", "", fmt.Errorf("credentials file %s must not be readable by everyone, but its mode is %s", path, info.Mode().Perm())
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	var profiles map[string]credentialProfile
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(data, &profiles); err != nil {
+			return "", "", fmt.Errorf("parse credentials file %s: %s", path, err)
+		}
+	} else if profiles, err = parseINIProfiles(data); err != nil {
+		return "", "", fmt.Errorf("parse credentials file %s: %s", path, err)
+	}
+
+	profile, ok := profiles[f.profile]
+	if !ok {
+		return "", "", fmt.Errorf("profile %q not found in credentials file %s", f.profile, path)
+	}
+	if profile.PublicKey == "" || profile.PrivateKey == "" {
+		return "", "", fmt.Errorf("profile %q in credentials file %s must have both public_key and private_key", f.profile, path)
+	}
+	return profile.PublicKey, profile.PrivateKey, nil
+}
+
+type credentialProfile struct {
+	PublicKey  string `json:"public_key"`
+	PrivateKey string `json:"private_key"`
+}
+
+// parseINIProfiles parses sections of public_key and private_key pairs.
+// Lines starting with # or ; are comments.
+func parseINIProfiles(data []byte) (map[string]credentialProfile, error) {
+	profiles := make(map[string]credentialProfile)
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		if section == "" {
+			return nil, fmt.Errorf("line %d: key outside of a profile section", n)
+		}
+		key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		profile := profiles[section]
+		switch key {
+		case "public_key":
+			profile.PublicKey = value
+		case "private_key":
+			profile.PrivateKey = value
+		}
+		profiles[section] = profile
+	}
+	return profiles, scanner.Err()
+}
+
+// WatchedFileCredentialProvider is a FileCredentialProvider that polls its file for
+// modifications and reports them through Changed, so a Client using it switches
+// to the new keys as soon as the file is updated. Close stops the polling.
+type WatchedFileCredentialProvider struct {
+	FileCredentialProvider
+
+	changed chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewWatchedFileCredentialProvider returns a WatchedFileCredentialProvider checking the
+// file for modifications at the given interval. An empty path stands for DefaultCredentialsFile
+// and an empty profile for "default".
+func NewWatchedFileCredentialProvider(path, profile string, interval time.Duration) (*WatchedFileCredentialProvider, error) {
+	if path == "" {
+		var err error
+		if path, err = DefaultCredentialsFile(); err != nil {
+			return nil, fmt.Errorf("find credentials file: %s", err)
+		}
+	}
+	if profile == "" {
+		profile = defaultProfile
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive, but got %s", interval)
+	}
+	w := &WatchedFileCredentialProvider{
+		FileCredentialProvider: FileCredentialProvider{path: path, profile: profile},
+		changed:                make(chan struct{}, 1),
+		done:                   make(chan struct{}),
+	}
+	go w.watch(interval, fileVersion(path))
+	return w, nil
+}
+
+// Changed implements CredentialNotifier.
+func (w *WatchedFileCredentialProvider) Changed() <-chan struct{} {
+	return w.changed
+}
+
+// Close stops watching the file.
+func (w *WatchedFileCredentialProvider) Close() error {
+	w.once.Do(func() { close(w.done) })
+	return nil
+}
+
+func (w *WatchedFileCredentialProvider) watch(interval time.Duration, last string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+		if version := fileVersion(w.path); version != last {
+			last = version
+			select {
+			case w.changed <- struct{}{}:
+			default: // A notification is already pending.
+			}
+		}
+	}
+}
+
+// fileVersion identifies the state of a file by its modification time and size.
+func fileVersion(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size())
+}
+
+// ChainCredentialProvider implements CredentialProvider by trying several providers in order.
+// The keys of the first provider not returning an error are used.
+type ChainCredentialProvider struct {
+	providers []CredentialProvider
+}
+
+// NewChainCredentialProvider returns a ChainCredentialProvider trying the given providers in order.
+func NewChainCredentialProvider(providers ...CredentialProvider) CredentialProvider {
+	return &ChainCredentialProvider{providers: providers}
+}
+
+// Keys returns public and private keys respectively.
+func (c *ChainCredentialProvider) Keys() (string, string, error) {
+	var errs []string
+	for _, provider := range c.providers {
+		publicKey, privateKey, err := provider.Keys()
+		if err == nil {
+			return publicKey, privateKey, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	if len(errs) == 0 {
+		return "", "", fmt.Errorf("no credential providers in chain")
+	}
+	return "", "", fmt.Errorf("no credential provider in chain succeeded: %s", strings.Join(errs, "; "))
+}
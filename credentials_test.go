@@ -0,0 +1,139 @@
+package mopinion
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCredentialsFile(t *testing.T, content string, mode os.FileMode) (string, func()) {
+	dir, err := ioutil.TempDir("", "mopinion")
+	if err != nil {
+		t.Fatalf("create temp dir: %s", err)
+	}
+	path := filepath.Join(dir, "credentials")
+	if err := ioutil.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatalf("write credentials file: %s", err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatalf("chmod credentials file: %s", err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func assertKeys(t *testing.T, provider CredentialProvider, expectedPublicKey, expectedPrivateKey string) {
+	t.Helper()
+	publicKey, privateKey, err := provider.Keys()
+	if err != nil {
+		t.Fatalf("provider should not return an error: %s", err)
+	}
+	if publicKey != expectedPublicKey {
+		t.Errorf("expected public key:%v but got:%v", expectedPublicKey, publicKey)
+	}
+	if privateKey != expectedPrivateKey {
+		t.Errorf("expected private key:%v but got:%v", expectedPrivateKey, privateKey)
+	}
+}
+
+func TestEnvCredentialProvider(t *testing.T) {
+	defer os.Unsetenv(PublicKeyEnv)
+	defer os.Unsetenv(PrivateKeyEnv)
+
+	os.Unsetenv(PublicKeyEnv)
+	os.Unsetenv(PrivateKeyEnv)
+	if _, _, err := NewEnvCredentialProvider().Keys(); err == nil {
+		t.Errorf("provider should return an error when the environment variables are not set")
+	}
+
+	os.Setenv(PublicKeyEnv, "envPublicKey")
+	os.Setenv(PrivateKeyEnv, "envPrivateKey")
+	assertKeys(t, NewEnvCredentialProvider(), "envPublicKey", "envPrivateKey")
+}
+
+func TestFileCredentialProviderJSON(t *testing.T) {
+	path, cleanup := writeCredentialsFile(t, `{
+		"default": {"public_key": "jsonPublicKey", "private_key": "jsonPrivateKey"},
+		"other": {"public_key": "otherPublicKey", "private_key": "otherPrivateKey"}
+	}`, 0600)
+	defer cleanup()
+
+	assertKeys(t, NewFileCredentialProvider(path, ""), "jsonPublicKey", "jsonPrivateKey")
+	assertKeys(t, NewFileCredentialProvider(path, "other"), "otherPublicKey", "otherPrivateKey")
+	if _, _, err := NewFileCredentialProvider(path, "missing").Keys(); err == nil {
+		t.Errorf("provider should return an error for a missing profile")
+	}
+}
+
+func TestFileCredentialProviderINI(t *testing.T) {
+	path, cleanup := writeCredentialsFile(t, `
+# production account
+[default]
+public_key = iniPublicKey
+private_key = iniPrivateKey
+
+; staging account
+[staging]
+public_key=stagingPublicKey
+private_key=stagingPrivateKey
+`, 0600)
+	defer cleanup()
+
+	assertKeys(t, NewFileCredentialProvider(path, ""), "iniPublicKey", "iniPrivateKey")
+	assertKeys(t, NewFileCredentialProvider(path, "staging"), "stagingPublicKey", "stagingPrivateKey")
+}
+
+func TestFileCredentialProviderInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		mode    os.FileMode
+	}{
+		{"world readable", "[default]\npublic_key = a\nprivate_key = b\n", 0644},
+		{"invalid json", `{"default": `, 0600},
+		{"key outside section", "public_key = a\n", 0600},
+		{"missing private key", "[default]\npublic_key = a\n", 0600},
+	}
+	for _, test := range tests {
+		path, cleanup := writeCredentialsFile(t, test.content, test.mode)
+		if _, _, err := NewFileCredentialProvider(path, "").Keys(); err == nil {
+			t.Errorf("%s: provider should return an error", test.name)
+		}
+		cleanup()
+	}
+
+	if _, _, err := NewFileCredentialProvider("/nonexistent/credentials", "").Keys(); err == nil {
+		t.Errorf("provider should return an error for a missing file")
+	}
+}
+
+type failingCredentialProvider struct{}
+
+func (failingCredentialProvider) Keys() (string, string, error) {
+	return "", "", errors.New("no keys")
+}
+
+func TestChainCredentialProvider(t *testing.T) {
+	chain := NewChainCredentialProvider(
+		failingCredentialProvider{},
+		NewBasicCredentialProvider("chainPublicKey", "chainPrivateKey"),
+		NewBasicCredentialProvider("unusedPublicKey", "unusedPrivateKey"),
+	)
+	assertKeys(t, chain, "chainPublicKey", "chainPrivateKey")
+
+	if _, _, err := NewChainCredentialProvider(failingCredentialProvider{}).Keys(); err == nil {
+		t.Errorf("chain should return an error when no provider succeeds")
+	}
+	if _, _, err := NewChainCredentialProvider().Keys(); err == nil {
+		t.Errorf("empty chain should return an error")
+	}
+
+	client, err := NewClient(chain)
+	if err != nil {
+		t.Fatalf("NewClient should not return an error: %s", err)
+	}
+	if client.publicKey != "chainPublicKey" {
+		t.Errorf("expected public key:%v but got:%v", "chainPublicKey", client.publicKey)
+	}
+}
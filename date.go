@@ -0,0 +1,145 @@
+package mopinion
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// timeLayouts are the layouts of the dates and times sent by the API, tried in order.
+var timeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+}
+
+// DateTime is a date, or a date and time, sent by the API. Dates sent without a time zone,
+// such as "2019-05-02", are parsed in UTC, or in the location set with WithDateLocation
+// when decoded by a Client. Raw keeps the text as it was sent. When the text has a format that is not known,
+// Time is zero and Raw still holds the text.
+type DateTime struct {
+	time.Time
+	Raw string
+}
+
+// String returns the date as it was sent by the API, or formatted if it was not.
+func (d DateTime) String() string {
+	if d.Raw != "" || d.Time.IsZero() {
+		return d.Raw
+	}
+	return d.format()
+}
+
+// ParseIn returns the date parsed from Raw in the given location, for dates sent without
+// a time zone that are known to be in another location than the one they were decoded in.
+// A nil location means UTC.
+func (d DateTime) ParseIn(location *time.Location) (time.Time, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	if d.Raw == "" {
+		return d.Time.In(location), nil
+	}
+	return parseTime(d.Raw, location)
+}
+
+func (d DateTime) format() string {
+	if h, m, s := d.Time.Clock(); h == 0 && m == 0 && s == 0 {
+		return d.Time.Format("2006-01-02")
+	}
+	return d.Time.Format("2006-01-02 15:04:05")
+}
+
+// UnmarshalJSON accepts the text of a date, an empty text or null, and a unix timestamp.
+// Dates without a time zone are parsed in UTC.
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	*d = DateTime{}
+	if string(data) == "null" {
+		return nil
+	}
+	if !strings.HasPrefix(string(data), `"`) {
+		var seconds int64
+		if err := json.Unmarshal(data, &seconds); err != nil {
+			return fmt.Errorf("date %s is neither text nor a timestamp", data)
+		}
+		d.Time = time.Unix(seconds, 0).UTC()
+		return nil
+	}
+	if err := json.Unmarshal(data, &d.Raw); err != nil {
+		return err
+	}
+	if d.Raw == "" {
+		return nil
+	}
+	if t, err := parseTime(d.Raw, time.UTC); err == nil {
+		d.Time = t
+	}
+	return nil
+}
+
+// MarshalJSON writes Raw if it is set, so dates are sent back as they were received.
+// A zero date is written as null.
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	if d.Raw == "" && d.Time.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// setLocation moves the date to the given location. Dates sent without a time zone are
+// parsed again, so they keep their clock time; other dates keep the same instant.
+func (d *DateTime) setLocation(location *time.Location) {
+	if d.Raw == "" {
+		if !d.Time.IsZero() {
+			d.Time = d.Time.In(location)
+		}
+		return
+	}
+	if t, err := parseTime(d.Raw, location); err == nil {
+		d.Time = t
+	}
+}
+
+var dateTimeType = reflect.TypeOf(DateTime{})
+
+// setDateLocation moves every DateTime reachable from v through pointers, exported struct
+// fields and slices to the given location. It is called on decoded responses.
+func setDateLocation(v reflect.Value, location *time.Location) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			setDateLocation(v.Elem(), location)
+		}
+	case reflect.Struct:
+		if v.Type() == dateTimeType {
+			if v.CanAddr() {
+				v.Addr().Interface().(*DateTime).setLocation(location)
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				setDateLocation(v.Field(i), location)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			setDateLocation(v.Index(i), location)
+		}
+	}
+}
+
+// parseTime parses s with the first of timeLayouts that fits, in the given location
+// unless s has a time zone.
+func parseTime(s string, location *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date format %q", s)
+}
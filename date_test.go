@@ -0,0 +1,104 @@
+package mopinion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDateTimeUnmarshal(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected time.Time
+		raw      string
+	}{
+		{`"2019-05-02"`, time.Date(2019, 5, 2, 0, 0, 0, 0, time.UTC), "2019-05-02"},
+		{`"2019-12-31 23:00:00"`, time.Date(2019, 12, 31, 23, 0, 0, 0, time.UTC), "2019-12-31 23:00:00"},
+		{`"2019-12-31T23:00:00+01:00"`, time.Date(2019, 12, 31, 22, 0, 0, 0, time.UTC), "2019-12-31T23:00:00+01:00"},
+		{`1556755200`, time.Date(2019, 5, 2, 0, 0, 0, 0, time.UTC), ""},
+		{`"next tuesday"`, time.Time{}, "next tuesday"},
+		{`""`, time.Time{}, ""},
+		{`null`, time.Time{}, ""},
+	}
+
+	for _, test := range tests {
+		var d DateTime
+		if err := json.Unmarshal([]byte(test.json), &d); err != nil {
+			t.Errorf("%s: unmarshaling should not return an error: %s", test.json, err)
+			continue
+		}
+		if !d.Time.Equal(test.expected) || d.Raw != test.raw {
+			t.Errorf("%s: expected time: %v and raw: %q but got: %v and %q", test.json, test.expected, test.raw, d.Time, d.Raw)
+		}
+	}
+}
+
+func TestDateTimeLocation(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"endDate": "2019-12-31 23:00:00", "Reports": [{"id": 1, "created": 1577833200}]}`)
+	})
+
+	location := time.FixedZone("CET", 60*60)
+	cet, err := NewClient(NewBasicCredentialProvider("publickey", "privatekey"),
+		WithBaseURL(client.BaseURL.String()), WithDateLocation(location))
+	if err != nil {
+		t.Fatalf("creating a client should not return an error: %s", err)
+	}
+	if cet.DateLocation() != location || client.DateLocation() != time.UTC {
+		t.Errorf("expected date locations CET and UTC but got: %v and %v", cet.DateLocation(), client.DateLocation())
+	}
+
+	account, _, err := cet.Account.Get(context.Background())
+	if err != nil {
+		t.Fatalf("account API should not return an error: %s", err)
+	}
+	if expected := time.Date(2019, 12, 31, 22, 0, 0, 0, time.UTC); !account.EndDate.Equal(expected) {
+		t.Errorf("expected end date: %v but got: %v", expected, account.EndDate.Time)
+	}
+	if created := account.Reports[0].Created; created.Location() != location || created.Hour() != 0 {
+		t.Errorf("expected the timestamp in CET but got: %v", created.Time)
+	}
+
+	// Other clients are not affected.
+	account, _, err = client.Account.Get(context.Background())
+	if expected := time.Date(2019, 12, 31, 23, 0, 0, 0, time.UTC); err != nil || !account.EndDate.Equal(expected) {
+		t.Errorf("expected end date: %v but got: %v, %v", expected, account.EndDate.Time, err)
+	}
+
+	utc, err := account.EndDate.ParseIn(location)
+	if expected := time.Date(2019, 12, 31, 22, 0, 0, 0, time.UTC); err != nil || !utc.Equal(expected) {
+		t.Errorf("expected end date: %v but got: %v, %v", expected, utc, err)
+	}
+
+	if _, err := NewClient(NewBasicCredentialProvider("publickey", "privatekey"), WithDateLocation(nil)); err == nil {
+		t.Errorf("a nil date location should return an error")
+	}
+}
+
+func TestDateTimeMarshal(t *testing.T) {
+	report := Report{Name: "report name"}
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("marshaling should not return an error: %s", err)
+	}
+	if expected := `{"name":"report name","Datasets":null}`; string(data) != expected {
+		t.Errorf("expected json: %s but got: %s", expected, data)
+	}
+
+	feedback := []FeedbackData{
+		{Created: DateTime{Raw: "2019-05-02"}},
+		{Created: DateTime{Time: time.Date(2019, 5, 2, 13, 4, 5, 0, time.UTC)}},
+		{},
+	}
+	for i, expected := range []string{`"2019-05-02"`, `"2019-05-02 13:04:05"`, `null`} {
+		data, err := json.Marshal(feedback[i].Created)
+		if err != nil || string(data) != expected {
+			t.Errorf("expected json: %s but got: %s, %v", expected, data, err)
+		}
+	}
+}
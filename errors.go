@@ -0,0 +1,212 @@
+package mopinion
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode is an error code returned by the Mopinion API.
+// It implements the error interface, so error codes can be used with errors.Is.
+// https://developer.mopinion.com/api/error-codes/
+type ErrorCode int
+
+// API error codes.
+const (
+	ErrorCodeUnknown ErrorCode = iota + 1
+	ErrorCodeServer
+	ErrorCodePublicKeyNotFound
+	ErrorCodeInvalidToken
+	ErrorCodeInvalidJSON
+	ErrorCodeRouteNotFound
+	ErrorCodeReportIDNotSet
+	ErrorCodeReportNotFound
+	ErrorCodeFailedToUpdateResource
+	ErrorCodeNotAuthorized
+	ErrorCodeMaxReportsReached
+	ErrorCodeInvalidRequest
+	ErrorCodeDatasetIDNotSet
+	ErrorCodeDatasetNotFound
+	ErrorCodeMaxDatasetsReached
+	ErrorCodeFailedToCreateResource
+	ErrorCodeNoDeploymentCode
+	ErrorCodeNotAuthenticated
+	ErrorCodePageOutOfRange
+	ErrorCodeDeploymentIDNotSet
+	ErrorCodeDeploymentNotFound
+	ErrorCodeOrganisationNotFound
+	ErrorCodeAccountNotFound
+)
+
+// Sentinel errors for the API error codes. The errors returned by the services
+// can be checked against them, e.g. errors.Is(err, ErrReportNotFound).
+var (
+	ErrUnknown                error = ErrorCodeUnknown
+	ErrServer                 error = ErrorCodeServer
+	ErrPublicKeyNotFound      error = ErrorCodePublicKeyNotFound
+	ErrInvalidToken           error = ErrorCodeInvalidToken
+	ErrInvalidJSON            error = ErrorCodeInvalidJSON
+	ErrRouteNotFound          error = ErrorCodeRouteNotFound
+	ErrReportIDNotSet         error = ErrorCodeReportIDNotSet
+	ErrReportNotFound         error = ErrorCodeReportNotFound
+	ErrFailedToUpdateResource error = ErrorCodeFailedToUpdateResource
+	ErrNotAuthorized          error = ErrorCodeNotAuthorized
+	ErrMaxReportsReached      error = ErrorCodeMaxReportsReached
+	ErrInvalidRequest         error = ErrorCodeInvalidRequest
+	ErrDatasetIDNotSet        error = ErrorCodeDatasetIDNotSet
+	ErrDatasetNotFound        error = ErrorCodeDatasetNotFound
+	ErrMaxDatasetsReached     error = ErrorCodeMaxDatasetsReached
+	ErrFailedToCreateResource error = ErrorCodeFailedToCreateResource
+	ErrNoDeploymentCode       error = ErrorCodeNoDeploymentCode
+	ErrNotAuthenticated       error = ErrorCodeNotAuthenticated
+	ErrPageOutOfRange         error = ErrorCodePageOutOfRange
+	ErrDeploymentIDNotSet     error = ErrorCodeDeploymentIDNotSet
+	ErrDeploymentNotFound     error = ErrorCodeDeploymentNotFound
+	ErrOrganisationNotFound   error = ErrorCodeOrganisationNotFound
+	ErrAccountNotFound        error = ErrorCodeAccountNotFound
+)
+
+type errorCodeInfo struct {
+	title       string
+	explanation string
+}
+
+// errorCodes holds the titles and explanations from the Mopinion API documentation.
+var errorCodes = map[ErrorCode]errorCodeInfo{
+	ErrorCodeUnknown: {
+		"Unknown / Generic error",
+		"The request was accepted and appears to be valid. There might be some additional information about what happened in the response body. If not, check your request carefully, and try again.",
+	},
+	ErrorCodeServer: {
+		"Server error",
+		"Something went wrong on the server side. The request can be tried again, just not too soon.",
+	},
+	ErrorCodePublicKeyNotFound: {
+		"Public key not found",
+		"The public key provided is incorrect.",
+	},
+	ErrorCodeInvalidToken: {
+		"Invalid token",
+		"The token provided in the x-auth-token header is incorrect.",
+	},
+	ErrorCodeInvalidJSON: {
+		"Invalid JSON",
+		"The request body is not properly formatted JSON.",
+	},
+	ErrorCodeRouteNotFound: {
+		"Route does not exist",
+		"The requested resource is not available, or at least not at this endpoint.",
+	},
+	ErrorCodeReportIDNotSet: {
+		"Report id is not set",
+		"A report id needs to be provided. Use the account endpoint to see valid ids.",
+	},
+	ErrorCodeReportNotFound: {
+		"Report not found",
+		"The report id provided does not point to a report in the account. Use the account endpoint to see valid report ids.",
+	},
+	ErrorCodeFailedToUpdateResource: {
+		"Failed to update the resource",
+		"The request appears to be ok, but something went wrong trying to process it. The request can be tried again.",
+	},
+	ErrorCodeNotAuthorized: {
+		"You don't have enough rights for this action",
+		"The request appears to be ok, but the account is not allowed to perform it.",
+	},
+	ErrorCodeMaxReportsReached: {
+		"Maximum number of reports reached",
+		"The maximum allowed number of reports can be checked through the account endpoint, or by visiting the billing page in the Mopinion Suite.",
+	},
+	ErrorCodeInvalidRequest: {
+		"One or more required fields are missing or invalid",
+		"The request body is formatted correctly, but the request can't be completed since required fields are missing or invalid.",
+	},
+	ErrorCodeDatasetIDNotSet: {
+		"Dataset id is not set",
+		"A dataset id needs to be provided. Use the account endpoint to see valid ids.",
+	},
+	ErrorCodeDatasetNotFound: {
+		"Dataset not found",
+		"The dataset id provided does not point to a dataset in the account. Use the account endpoint to see valid dataset ids.",
+	},
+	ErrorCodeMaxDatasetsReached: {
+		"Maximum number of datasets reached",
+		"The maximum allowed number of datasets can be checked through the account endpoint, or by visiting the billing page in the Mopinion Suite.",
+	},
+	ErrorCodeFailedToCreateResource: {
+		"Failed to create the resource",
+		"The request appears to be ok, but something went wrong trying to save it. The request can be tried again.",
+	},
+	ErrorCodeNoDeploymentCode: {
+		"There are no deployment codes for your account",
+		"There are no deployment codes associated with the account. They can be created in the Mopinion Suite.",
+	},
+	ErrorCodeNotAuthenticated: {
+		"The credentials you provided are not valid",
+		"The resource needs credentials in the form of a Basic Authorization header built from the public and private keys, not an x-auth-token header.",
+	},
+	ErrorCodePageOutOfRange: {
+		"There are no results for the page number you requested",
+		"The resource collection does exist, but the page number provided exceeds the number of pages available.",
+	},
+	ErrorCodeDeploymentIDNotSet: {
+		"Deployment id is not set",
+		"A deployment id needs to be provided.",
+	},
+	ErrorCodeDeploymentNotFound: {
+		"Deployment not found",
+		"There is no deployment for the deployment id provided.",
+	},
+	ErrorCodeOrganisationNotFound: {
+		"Organisation not found",
+		"There is no organisation with the id provided.",
+	},
+	ErrorCodeAccountNotFound: {
+		"Account not found",
+		"The credentials and signature are valid, but there is no data associated with them.",
+	},
+}
+
+// Title returns the documented title of the error code.
+func (c ErrorCode) Title() string {
+	if info, ok := errorCodes[c]; ok {
+		return info.title
+	}
+	return fmt.Sprintf("error code %d", int(c))
+}
+
+// Explanation returns the documented explanation of the error code.
+func (c ErrorCode) Explanation() string {
+	return errorCodes[c].explanation
+}
+
+// Error implements the error interface.
+func (c ErrorCode) Error() string {
+	return fmt.Sprintf("mopinion: %s", c.Title())
+}
+
+// errorResponse returns the ErrorResponse behind err, or nil if err does not come from the API.
+func errorResponse(err error) *ErrorResponse {
+	var r *ErrorResponse
+	if errors.As(err, &r) {
+		return r
+	}
+	return nil
+}
+
+// errorCode returns the Mopinion error code carried by err, or zero if there is none.
+func errorCode(err error) ErrorCode {
+	if r := errorResponse(err); r != nil {
+		return ErrorCode(r.ErrorCode)
+	}
+	return 0
+}
+
+// isTokenError reports whether err means the token or the keys used to sign the request are not accepted.
+func isTokenError(err error) bool {
+	return errors.Is(err, ErrInvalidToken) || isCredentialError(err)
+}
+
+// isCredentialError reports whether err means the keys are not accepted.
+func isCredentialError(err error) bool {
+	return errors.Is(err, ErrPublicKeyNotFound) || errors.Is(err, ErrNotAuthenticated)
+}
@@ -0,0 +1,76 @@
+package mopinion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestErrorCodeSentinels(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"status": 404, "error_code": 8, "title": "Report not found"}`)
+	})
+
+	_, _, err := client.Reports.Get(context.Background(), 1)
+	if !errors.Is(err, ErrReportNotFound) {
+		t.Errorf("err should be ErrReportNotFound but received error: %v", err)
+	}
+	if errors.Is(err, ErrDatasetNotFound) {
+		t.Errorf("err should not be ErrDatasetNotFound")
+	}
+
+	var code ErrorCode
+	if !errors.As(err, &code) || code != ErrorCodeReportNotFound {
+		t.Errorf("expected error code: %v but got: %v", ErrorCodeReportNotFound, code)
+	}
+
+	var errorResponse *ErrorResponse
+	if !errors.As(err, &errorResponse) {
+		t.Fatalf("err should be an ErrorResponse but received error: %v", err)
+	}
+	if errorResponse.Explanation() != ErrorCodeReportNotFound.Explanation() {
+		t.Errorf("expected explanation: %v but got: %v", ErrorCodeReportNotFound.Explanation(), errorResponse.Explanation())
+	}
+}
+
+func TestErrorCodeAuthenticationError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"status": 401, "error_code": 18, "title": "The credentials you provided are not valid"}`)
+	})
+
+	_, _, err := client.Account.Get(context.Background())
+	if _, ok := err.(*AuthenticationError); !ok {
+		t.Errorf("err should be an AuthenticationError but received error: %v", err)
+	}
+	if !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("err should be ErrNotAuthenticated but received error: %v", err)
+	}
+	var errorResponse *ErrorResponse
+	if !errors.As(err, &errorResponse) || errorResponse.ErrorCode != int(ErrorCodeNotAuthenticated) {
+		t.Errorf("err should unwrap to an ErrorResponse but received error: %v", err)
+	}
+}
+
+func TestErrorCodeTitles(t *testing.T) {
+	for code := ErrorCodeUnknown; code <= ErrorCodeAccountNotFound; code++ {
+		if _, ok := errorCodes[code]; !ok {
+			t.Errorf("error code %d has no title and explanation", code)
+		}
+	}
+	if ErrPageOutOfRange.Error() != "mopinion: There are no results for the page number you requested" {
+		t.Errorf("unexpected error message: %v", ErrPageOutOfRange)
+	}
+	if ErrorCode(99).Title() != "error code 99" {
+		t.Errorf("unexpected title for an undocumented error code: %v", ErrorCode(99).Title())
+	}
+}
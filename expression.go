@@ -0,0 +1,241 @@
+package mopinion
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// filterOperators maps the operators of filter expressions to modifiers. Longer operators
+// come first so that they are matched before their prefixes; = is written for no modifier.
+var filterOperators = []struct {
+	operator string
+	modifier FilterModifier
+}{
+	{">=", Gte},
+	{"<=", Lte},
+	{"!=", Not},
+	{"==", ""},
+	{">", Gt},
+	{"<", Lt},
+	{"=", ""},
+}
+
+// FilterSyntaxError is returned by ParseFilters when an expression cannot be parsed,
+// or one of its values is invalid for its key.
+type FilterSyntaxError struct {
+	Expression string
+	// Column is the 1-based position in Expression where the error was found.
+	Column  int
+	Message string
+}
+
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("invalid filter expression at column %d: %s", e.Column, e.Message)
+}
+
+// ParseFilters parses a filter expression such as
+//
+//	nps >= 9 and date >= 2019-10-01 and tags != spam
+//
+// into a FilterCollection. An expression is a list of comparisons joined by "and", where
+// each comparison is a FilterKey, an operator (=, !=, <, <=, > or >=) and a value. Values
+// containing spaces can be written in double quotes. Values are validated like FilterBuilder
+// does, and dates use FilterDateLayout. An empty expression returns an empty collection.
+func ParseFilters(expression string) (*FilterCollection, error) {
+	p := &filterParser{expression: expression}
+	collection := &FilterCollection{}
+	p.skipSpaces()
+	for p.pos < len(p.expression) {
+		if len(collection.Filters) > 0 {
+			if err := p.and(); err != nil {
+				return nil, err
+			}
+		}
+		filters, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		collection.Filters = append(collection.Filters, filters...)
+		p.skipSpaces()
+	}
+	return collection, nil
+}
+
+// String returns the filter as a comparison of a filter expression.
+func (f Filter) String() string {
+	operator := "="
+	for _, o := range filterOperators {
+		if o.modifier != "" && o.modifier == f.Modifier {
+			operator = o.operator
+			break
+		}
+	}
+	value := f.Value
+	if value == "" || strings.HasPrefix(value, `"`) || strings.IndexFunc(value, unicode.IsSpace) >= 0 {
+		value = strconv.Quote(value)
+	}
+	return fmt.Sprintf("%s %s %s", f.Key, operator, value)
+}
+
+// String returns the filters as an expression that ParseFilters turns back into the same collection.
+func (c FilterCollection) String() string {
+	comparisons := make([]string, len(c.Filters))
+	for i, f := range c.Filters {
+		comparisons[i] = f.String()
+	}
+	return strings.Join(comparisons, " and ")
+}
+
+type filterParser struct {
+	expression string
+	pos        int
+}
+
+func (p *filterParser) errorf(pos int, format string, args ...interface{}) error {
+	return &FilterSyntaxError{Expression: p.expression, Column: pos + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *filterParser) skipSpaces() {
+	for p.pos < len(p.expression) && unicode.IsSpace(rune(p.expression[p.pos])) {
+		p.pos++
+	}
+}
+
+// word reads the run of characters up to the next space or operator character.
+func (p *filterParser) word() string {
+	start := p.pos
+	for p.pos < len(p.expression) && !unicode.IsSpace(rune(p.expression[p.pos])) && !strings.ContainsRune("<>=!\"", rune(p.expression[p.pos])) {
+		p.pos++
+	}
+	return p.expression[start:p.pos]
+}
+
+func (p *filterParser) and() error {
+	start := p.pos
+	if word := p.word(); !strings.EqualFold(word, "and") {
+		return p.errorf(start, "expected \"and\" but found %q", p.rest(start))
+	}
+	p.skipSpaces()
+	if p.pos == len(p.expression) {
+		return p.errorf(p.pos, "expected a comparison after \"and\"")
+	}
+	return nil
+}
+
+func (p *filterParser) comparison() ([]Filter, error) {
+	start := p.pos
+	name := p.word()
+	if name == "" {
+		return nil, p.errorf(start, "expected a filter key but found %q", p.rest(start))
+	}
+	key := FilterKey(strings.ToLower(name))
+	switch key {
+	case Date, Rating, Nps, Ces, CesInverse, Gcr, Tags:
+	default:
+		return nil, p.errorf(start, "unknown filter key %q", name)
+	}
+
+	p.skipSpaces()
+	modifier, ok := p.operator()
+	if !ok {
+		return nil, p.errorf(p.pos, "expected an operator after %q but found %q", name, p.rest(p.pos))
+	}
+
+	p.skipSpaces()
+	valueStart := p.pos
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err := buildFilter(key, modifier, value)
+	if err != nil {
+		return nil, p.errorf(valueStart, "%s", err)
+	}
+	return filters, nil
+}
+
+func (p *filterParser) operator() (FilterModifier, bool) {
+	for _, o := range filterOperators {
+		if strings.HasPrefix(p.expression[p.pos:], o.operator) {
+			p.pos += len(o.operator)
+			return o.modifier, true
+		}
+	}
+	return "", false
+}
+
+func (p *filterParser) value() (string, error) {
+	start := p.pos
+	if p.pos == len(p.expression) {
+		return "", p.errorf(start, "expected a value")
+	}
+	if p.expression[p.pos] != '"' {
+		for p.pos < len(p.expression) && !unicode.IsSpace(rune(p.expression[p.pos])) {
+			p.pos++
+		}
+		return p.expression[start:p.pos], nil
+	}
+
+	for p.pos++; p.pos < len(p.expression); p.pos++ {
+		switch p.expression[p.pos] {
+		case '\\':
+			p.pos++
+		case '"':
+			p.pos++
+			value, err := strconv.Unquote(p.expression[start:p.pos])
+			if err != nil {
+				return "", p.errorf(start, "invalid quoted value %s", p.expression[start:p.pos])
+			}
+			return value, nil
+		}
+	}
+	return "", p.errorf(start, "unterminated quoted value")
+}
+
+// rest returns the remainder of the expression from pos, shortened for error messages.
+func (p *filterParser) rest(pos int) string {
+	rest := p.expression[pos:]
+	if len(rest) > 20 {
+		rest = rest[:20] + "..."
+	}
+	return rest
+}
+
+// buildFilter validates a single comparison with a FilterBuilder.
+func buildFilter(key FilterKey, modifier FilterModifier, value string) ([]Filter, error) {
+	b := NewFilters()
+	switch key {
+	case Date:
+		t, err := time.Parse(FilterDateLayout, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: value %q is not a date like %s", key, value, FilterDateLayout)
+		}
+		b.Date().add(modifier, t)
+	case Rating, Nps, Ces, CesInverse:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: value %q is not a number", key, value)
+		}
+		numbers := map[FilterKey]func() *NumberFilter{Rating: b.Rating, Nps: b.NPS, Ces: b.CES, CesInverse: b.CESInverse}
+		numbers[key]().add(modifier, number)
+	case Gcr, Tags:
+		if modifier != "" && modifier != Not {
+			return nil, fmt.Errorf("%s: only = and != can be used", key)
+		}
+		if key == Gcr {
+			b.GCR().add(modifier, value)
+		} else {
+			b.Tags().add(modifier, value)
+		}
+	}
+
+	filters, err := b.Build()
+	if err != nil {
+		return nil, err.(FilterErrors)[0]
+	}
+	return filters.Filters, nil
+}
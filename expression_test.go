@@ -0,0 +1,84 @@
+package mopinion
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseFilters(t *testing.T) {
+	filters, err := ParseFilters(`nps >= 9 and date>=2019-10-01 AND tags != spam and gcr = partly and tags == "spam & eggs"`)
+	if err != nil {
+		t.Fatalf("parsing should not return an error: %s", err)
+	}
+
+	expected := []Filter{
+		{Key: Nps, Modifier: Gte, Value: "9"},
+		{Key: Date, Modifier: Gte, Value: "2019-10-01"},
+		{Key: Tags, Modifier: Not, Value: "spam"},
+		{Key: Gcr, Value: "partly"},
+		{Key: Tags, Value: "spam & eggs"},
+	}
+	if !reflect.DeepEqual(expected, filters.Filters) {
+		t.Errorf("expected filters: %v but got: %v", expected, filters.Filters)
+	}
+
+	if empty, err := ParseFilters("  "); err != nil || len(empty.Filters) != 0 {
+		t.Errorf("expected no filters but got: %v, %v", empty, err)
+	}
+}
+
+func TestFilterCollectionString(t *testing.T) {
+	collection := &FilterCollection{Filters: []Filter{
+		{Key: Nps, Modifier: Gte, Value: "9"},
+		{Key: Date, Modifier: Lt, Value: "2019-10-01"},
+		{Key: Rating, Modifier: Lte, Value: "3.5"},
+		{Key: Tags, Modifier: Not, Value: "spam & eggs"},
+		{Key: Ces, Modifier: Gt, Value: "2"},
+		{Key: Gcr, Value: "yes"},
+	}}
+
+	expression := collection.String()
+	if expected := `nps >= 9 and date < 2019-10-01 and rating <= 3.5 and tags != "spam & eggs" and ces > 2 and gcr = yes`; expression != expected {
+		t.Errorf("expected expression: %v but got: %v", expected, expression)
+	}
+
+	parsed, err := ParseFilters(expression)
+	if err != nil {
+		t.Fatalf("parsing should not return an error: %s", err)
+	}
+	if !reflect.DeepEqual(collection, parsed) {
+		t.Errorf("expected filters: %v but got: %v", collection, parsed)
+	}
+}
+
+func TestParseFiltersErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		column     int
+		message    string
+	}{
+		{"nps >= 9 or tags = spam", 10, `expected "and" but found "or tags = spam"`},
+		{"score > 3", 1, `unknown filter key "score"`},
+		{"nps 9", 5, `expected an operator after "nps" but found "9"`},
+		{"nps >=", 7, "expected a value"},
+		{"nps >= 11", 8, "nps: value 11 must be between 0 and 10"},
+		{"date >= 01-10-2019", 9, `date: value "01-10-2019" is not a date like 2006-01-02`},
+		{"ces = low", 7, `ces: value "low" is not a number`},
+		{"tags > spam", 8, "tags: only = and != can be used"},
+		{`tags = "spam`, 8, "unterminated quoted value"},
+		{"nps >= 9 and", 13, `expected a comparison after "and"`},
+	}
+
+	for _, test := range tests {
+		_, err := ParseFilters(test.expression)
+		var syntaxErr *FilterSyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%q: expected FilterSyntaxError but got: %v", test.expression, err)
+			continue
+		}
+		if syntaxErr.Column != test.column || syntaxErr.Message != test.message {
+			t.Errorf("%q: expected error at column %d: %q but got column %d: %q", test.expression, test.column, test.message, syntaxErr.Column, syntaxErr.Message)
+		}
+	}
+}
@@ -3,14 +3,24 @@ package mopinion
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"strings"
 )
 
-// FeedbackInterface holds two methods that return feedback by a given dataset or report.
-// FeedbackInterface accepts pagination options and filters.
+// FeedbackInterface holds methods that return feedback by a given dataset or report,
+// either a single page at a time, through an iterator walking all pages, or all pages
+// fetched in parallel. FeedbackInterface accepts pagination options and filters.
 type FeedbackInterface interface {
 	GetByDataset(ctx context.Context, datasetID int, options *PaginationOptions, filters *FilterCollection) (*Feedback, *Response, error)
 	GetByReport(ctx context.Context, reportID int, options *PaginationOptions, filters *FilterCollection) (*Feedback, *Response, error)
+	FollowNext(ctx context.Context, meta *Meta) (*Feedback, *Response, error)
+	FollowPrevious(ctx context.Context, meta *Meta) (*Feedback, *Response, error)
+	IterateByDataset(datasetID int, options *PaginationOptions, filters *FilterCollection) *FeedbackIterator
+	IterateByReport(reportID int, options *PaginationOptions, filters *FilterCollection) *FeedbackIterator
+	BulkByDataset(ctx context.Context, datasetID int, options *BulkOptions, filters *FilterCollection) ([]FeedbackData, error)
+	BulkByReport(ctx context.Context, reportID int, options *BulkOptions, filters *FilterCollection) ([]FeedbackData, error)
+	StreamByDataset(ctx context.Context, datasetID int, options *BulkOptions, filters *FilterCollection) <-chan FeedbackResult
+	StreamByReport(ctx context.Context, reportID int, options *BulkOptions, filters *FilterCollection) <-chan FeedbackResult
 }
 
 // FeedbackService implements FeedbackInterface.
@@ -46,9 +56,9 @@ type Filter struct {
 	Value    string
 }
 
-// Build returns the string to be appended to the URL.
+// Build returns the string to be appended to the URL. The value is escaped.
 func (f Filter) Build() string {
-	return fmt.Sprintf("filter[%s%s]=%s", f.Modifier, f.Key, f.Value)
+	return fmt.Sprintf("filter[%s%s]=%s", f.Modifier, f.Key, url.QueryEscape(f.Value))
 }
 
 // FilterKey type represents the possible filters
@@ -63,7 +73,7 @@ const (
 	Nps FilterKey = "nps"
 	// Ces is Customer Effort Score, its value should be between 1 and 5.
 	Ces FilterKey = "ces"
-	// CesInverse is Customer Effort Score
+	// CesInverse is the inverse Customer Effort Score, its value should be between 1 and 5.
 	CesInverse FilterKey = "ces_inverse"
 	// Gcr is Goal Completion Rate. Option are no, partly, yes.
 	Gcr FilterKey = "gcr"
@@ -5,10 +5,13 @@ import (
 	"fmt"
 )
 
-// FieldsInterface has two methods returning fields for a specific dataset or report.
+// FieldsInterface has methods returning fields for a specific dataset or report,
+// and following the links to the next and previous pages.
 type FieldsInterface interface {
 	GetByDataset(ctx context.Context, datasetID int) (*Fields, *Response, error)
 	GetByReport(ctx context.Context, reportID int) (*Fields, *Response, error)
+	FollowNext(ctx context.Context, meta *Meta) (*Fields, *Response, error)
+	FollowPrevious(ctx context.Context, meta *Meta) (*Fields, *Response, error)
 }
 
 // FieldsService implements FieldsInterface
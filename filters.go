@@ -0,0 +1,218 @@
+package mopinion
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterDateLayout is the layout of date filter values.
+const FilterDateLayout = "2006-01-02"
+
+// Goal Completion Rate values accepted by the Gcr filter.
+const (
+	GcrNo     = "no"
+	GcrPartly = "partly"
+	GcrYes    = "yes"
+)
+
+// FilterErrors holds every validation error found while building filters.
+type FilterErrors []error
+
+func (e FilterErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid filters: %s", strings.Join(messages, "; "))
+}
+
+// FilterBuilder builds a validated FilterCollection in a fluent way:
+//
+//	filters, err := mopinion.NewFilters().
+//		NPS().Gte(9).
+//		Date().Between(from, to).
+//		Tags().Not("spam").
+//		Build()
+//
+// Values are checked against the ranges documented on the FilterKey constants.
+// Build reports all validation errors together.
+type FilterBuilder struct {
+	filters []Filter
+	errs    FilterErrors
+}
+
+// NewFilters returns an empty FilterBuilder.
+func NewFilters() *FilterBuilder {
+	return &FilterBuilder{}
+}
+
+// Build returns the filters, or FilterErrors if any of them is invalid.
+func (b *FilterBuilder) Build() (*FilterCollection, error) {
+	if len(b.errs) > 0 {
+		return nil, b.errs
+	}
+	return &FilterCollection{Filters: append([]Filter(nil), b.filters...)}, nil
+}
+
+func (b *FilterBuilder) add(key FilterKey, modifier FilterModifier, value string) *FilterBuilder {
+	b.filters = append(b.filters, Filter{Key: key, Modifier: modifier, Value: value})
+	return b
+}
+
+func (b *FilterBuilder) fail(key FilterKey, format string, args ...interface{}) *FilterBuilder {
+	b.errs = append(b.errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	return b
+}
+
+// Date filters on the time when the feedback was created.
+func (b *FilterBuilder) Date() *DateFilter {
+	return &DateFilter{b}
+}
+
+// Rating filters on a general numeric rating.
+func (b *FilterBuilder) Rating() *NumberFilter {
+	return &NumberFilter{builder: b, key: Rating, integer: false}
+}
+
+// NPS filters on the Net Promotor Score, between 0 and 10.
+func (b *FilterBuilder) NPS() *NumberFilter {
+	return &NumberFilter{builder: b, key: Nps, integer: true, bounded: true, min: 0, max: 10}
+}
+
+// CES filters on the Customer Effort Score, between 1 and 5.
+func (b *FilterBuilder) CES() *NumberFilter {
+	return &NumberFilter{builder: b, key: Ces, integer: true, bounded: true, min: 1, max: 5}
+}
+
+// CESInverse filters on the inverse Customer Effort Score, between 1 and 5.
+func (b *FilterBuilder) CESInverse() *NumberFilter {
+	return &NumberFilter{builder: b, key: CesInverse, integer: true, bounded: true, min: 1, max: 5}
+}
+
+// GCR filters on the Goal Completion Rate, one of GcrNo, GcrPartly and GcrYes.
+func (b *FilterBuilder) GCR() *ChoiceFilter {
+	return &ChoiceFilter{builder: b, key: Gcr, choices: []string{GcrNo, GcrPartly, GcrYes}}
+}
+
+// Tags filters on the tags assigned to the feedback.
+func (b *FilterBuilder) Tags() *ChoiceFilter {
+	return &ChoiceFilter{builder: b, key: Tags}
+}
+
+// NumberFilter adds a filter on a numeric key to its FilterBuilder.
+type NumberFilter struct {
+	builder  *FilterBuilder
+	key      FilterKey
+	integer  bool
+	bounded  bool
+	min, max float64
+}
+
+func (f *NumberFilter) add(modifier FilterModifier, value float64) *FilterBuilder {
+	if f.integer && value != float64(int64(value)) {
+		return f.builder.fail(f.key, "value %v must be a whole number", value)
+	}
+	if f.bounded && (value < f.min || value > f.max) {
+		return f.builder.fail(f.key, "value %v must be between %v and %v", value, f.min, f.max)
+	}
+	return f.builder.add(f.key, modifier, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// Eq keeps the feedback where the key equals value.
+func (f *NumberFilter) Eq(value float64) *FilterBuilder { return f.add("", value) }
+
+// Not keeps the feedback where the key does not equal value.
+func (f *NumberFilter) Not(value float64) *FilterBuilder { return f.add(Not, value) }
+
+// Lt keeps the feedback where the key is less than value.
+func (f *NumberFilter) Lt(value float64) *FilterBuilder { return f.add(Lt, value) }
+
+// Lte keeps the feedback where the key is less than or equal to value.
+func (f *NumberFilter) Lte(value float64) *FilterBuilder { return f.add(Lte, value) }
+
+// Gt keeps the feedback where the key is greater than value.
+func (f *NumberFilter) Gt(value float64) *FilterBuilder { return f.add(Gt, value) }
+
+// Gte keeps the feedback where the key is greater than or equal to value.
+func (f *NumberFilter) Gte(value float64) *FilterBuilder { return f.add(Gte, value) }
+
+// Between keeps the feedback where the key is between from and to, both inclusive.
+func (f *NumberFilter) Between(from, to float64) *FilterBuilder {
+	if from > to {
+		return f.builder.fail(f.key, "range %v to %v is empty", from, to)
+	}
+	f.add(Gte, from)
+	return f.add(Lte, to)
+}
+
+// DateFilter adds a filter on the creation date to its FilterBuilder.
+// Only the date part of the times is used, in their own location.
+type DateFilter struct {
+	builder *FilterBuilder
+}
+
+func (f *DateFilter) add(modifier FilterModifier, t time.Time) *FilterBuilder {
+	if t.IsZero() {
+		return f.builder.fail(Date, "date must be set")
+	}
+	return f.builder.add(Date, modifier, t.Format(FilterDateLayout))
+}
+
+// Eq keeps the feedback created on the date of t.
+func (f *DateFilter) Eq(t time.Time) *FilterBuilder { return f.add("", t) }
+
+// Not keeps the feedback not created on the date of t.
+func (f *DateFilter) Not(t time.Time) *FilterBuilder { return f.add(Not, t) }
+
+// Lt keeps the feedback created before the date of t.
+func (f *DateFilter) Lt(t time.Time) *FilterBuilder { return f.add(Lt, t) }
+
+// Lte keeps the feedback created on or before the date of t.
+func (f *DateFilter) Lte(t time.Time) *FilterBuilder { return f.add(Lte, t) }
+
+// Gt keeps the feedback created after the date of t.
+func (f *DateFilter) Gt(t time.Time) *FilterBuilder { return f.add(Gt, t) }
+
+// Gte keeps the feedback created on or after the date of t.
+func (f *DateFilter) Gte(t time.Time) *FilterBuilder { return f.add(Gte, t) }
+
+// Between keeps the feedback created from the date of from to the date of to, both inclusive.
+func (f *DateFilter) Between(from, to time.Time) *FilterBuilder {
+	if from.After(to) {
+		return f.builder.fail(Date, "range %s to %s is empty", from.Format(FilterDateLayout), to.Format(FilterDateLayout))
+	}
+	f.add(Gte, from)
+	return f.add(Lte, to)
+}
+
+// ChoiceFilter adds a filter on a textual key to its FilterBuilder.
+// If choices is set, values must be one of them.
+type ChoiceFilter struct {
+	builder *FilterBuilder
+	key     FilterKey
+	choices []string
+}
+
+func (f *ChoiceFilter) add(modifier FilterModifier, value string) *FilterBuilder {
+	if value == "" {
+		return f.builder.fail(f.key, "value cannot be empty")
+	}
+	if len(f.choices) > 0 {
+		valid := false
+		for _, choice := range f.choices {
+			valid = valid || value == choice
+		}
+		if !valid {
+			return f.builder.fail(f.key, "value %q must be one of %s", value, strings.Join(f.choices, ", "))
+		}
+	}
+	return f.builder.add(f.key, modifier, value)
+}
+
+// Eq keeps the feedback where the key equals value.
+func (f *ChoiceFilter) Eq(value string) *FilterBuilder { return f.add("", value) }
+
+// Not keeps the feedback where the key does not equal value.
+func (f *ChoiceFilter) Not(value string) *FilterBuilder { return f.add(Not, value) }
@@ -0,0 +1,69 @@
+package mopinion
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFilterBuilder(t *testing.T) {
+	from := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	to := time.Date(2019, 10, 31, 0, 0, 0, 0, time.UTC)
+
+	filters, err := NewFilters().NPS().Gte(9).Date().Between(from, to).Tags().Not("spam").GCR().Eq(GcrPartly).Rating().Lt(3.5).Build()
+	if err != nil {
+		t.Fatalf("building valid filters should not return an error: %s", err)
+	}
+
+	expected := []Filter{
+		{Key: Nps, Modifier: Gte, Value: "9"},
+		{Key: Date, Modifier: Gte, Value: "2019-10-01"},
+		{Key: Date, Modifier: Lte, Value: "2019-10-31"},
+		{Key: Tags, Modifier: Not, Value: "spam"},
+		{Key: Gcr, Value: "partly"},
+		{Key: Rating, Modifier: Lt, Value: "3.5"},
+	}
+	if !reflect.DeepEqual(expected, filters.Filters) {
+		t.Errorf("expected filters: %v but got: %v", expected, filters.Filters)
+	}
+}
+
+func TestFilterBuilderErrors(t *testing.T) {
+	from := time.Date(2019, 10, 31, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	filters, err := NewFilters().NPS().Gt(11).CES().Eq(0).CESInverse().Lte(2.5).GCR().Eq("maybe").Tags().Eq("").Date().Between(from, to).Date().Gte(time.Time{}).Build()
+	if filters != nil {
+		t.Errorf("filters should be nil but got: %v", filters)
+	}
+
+	var errs FilterErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected FilterErrors but got: %v", err)
+	}
+	expected := []string{
+		"nps: value 11 must be between 0 and 10",
+		"ces: value 0 must be between 1 and 5",
+		"ces_inverse: value 2.5 must be a whole number",
+		`gcr: value "maybe" must be one of no, partly, yes`,
+		"tags: value cannot be empty",
+		"date: range 2019-10-31 to 2019-10-01 is empty",
+		"date: date must be set",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors but got: %v", len(expected), errs)
+	}
+	for i, err := range errs {
+		if err.Error() != expected[i] {
+			t.Errorf("expected error: %q but got: %q", expected[i], err)
+		}
+	}
+}
+
+func TestFilterBuildEscapesValue(t *testing.T) {
+	filter := Filter{Key: Tags, Modifier: Not, Value: "spam & eggs"}
+	if expected := "filter[!tags]=spam+%26+eggs"; filter.Build() != expected {
+		t.Errorf("expected filter: %v but got: %v", expected, filter.Build())
+	}
+}
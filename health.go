@@ -0,0 +1,85 @@
+package mopinion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthCheck checks the connectivity to the Mopinion API by pinging it,
+// then verifies the credentials and the token by getting the account.
+func (c *Client) HealthCheck(ctx context.Context) error {
+	if _, err := c.Ping.Get(ctx); err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+	if _, _, err := c.Account.Get(ctx); err != nil {
+		return fmt.Errorf("get account: %w", err)
+	}
+	return nil
+}
+
+// Health is the outcome of a health check, as reported by HealthHandler.
+type Health struct {
+	Status      string     `json:"status"`
+	Latency     string     `json:"latency"`
+	CheckedAt   time.Time  `json:"checked_at"`
+	Error       string     `json:"error,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// HealthHandler is an http.Handler running Client.HealthCheck on every request,
+// e.g. when mounted as /healthz/mopinion. It responds with a Health as JSON and
+// the status code 200 if the check passes, 503 otherwise.
+type HealthHandler struct {
+	client  *Client
+	timeout time.Duration
+
+	mu          sync.Mutex
+	lastError   string
+	lastErrorAt *time.Time
+}
+
+// NewHealthHandler returns a HealthHandler for the client. Every check is limited
+// to the given timeout, if it is positive.
+func NewHealthHandler(client *Client, timeout time.Duration) *HealthHandler {
+	return &HealthHandler{client: client, timeout: timeout}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := h.client.HealthCheck(ctx)
+	health := &Health{
+		Status:    "ok",
+		Latency:   time.Since(start).String(),
+		CheckedAt: start.UTC(),
+	}
+
+	h.mu.Lock()
+	if err != nil {
+		health.Status = "error"
+		health.Error = err.Error()
+		h.lastError = health.Error
+		h.lastErrorAt = &health.CheckedAt
+	}
+	health.LastError = h.lastError
+	health.LastErrorAt = h.lastErrorAt
+	h.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(health)
+}
@@ -0,0 +1,75 @@
+package mopinion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthCheck(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	pingStatus := http.StatusOK
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(pingStatus)
+		if pingStatus != http.StatusOK {
+			fmt.Fprint(w, `{"status": 500, "error_code": 2, "title": "Server error"}`)
+		}
+	})
+	accountStatus := http.StatusOK
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(accountStatus)
+		if accountStatus != http.StatusOK {
+			fmt.Fprint(w, `{"status": 401, "error_code": 18, "title": "The credentials you provided are not valid"}`)
+			return
+		}
+		fmt.Fprint(w, `{"name": "account name"}`)
+	})
+
+	handler := NewHealthHandler(client, time.Second)
+	check := func(expectedStatus int) *Health {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/healthz/mopinion", nil))
+		if recorder.Code != expectedStatus {
+			t.Errorf("expected status code: %v but got: %v", expectedStatus, recorder.Code)
+		}
+		health := new(Health)
+		if err := json.Unmarshal(recorder.Body.Bytes(), health); err != nil {
+			t.Fatalf("unmarshaling should not return an error: %s", err)
+		}
+		if health.Latency == "" {
+			t.Errorf("latency should be reported")
+		}
+		return health
+	}
+
+	if health := check(http.StatusOK); health.Status != "ok" || health.LastError != "" {
+		t.Errorf("unexpected health: %+v", health)
+	}
+
+	accountStatus = http.StatusUnauthorized
+	if err := client.HealthCheck(context.Background()); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("err should be ErrNotAuthenticated but received error: %v", err)
+	}
+	if health := check(http.StatusServiceUnavailable); health.Status != "error" || health.Error == "" {
+		t.Errorf("unexpected health: %+v", health)
+	}
+
+	accountStatus = http.StatusOK
+	pingStatus = http.StatusInternalServerError
+	if err := client.HealthCheck(context.Background()); !errors.Is(err, ErrServer) {
+		t.Errorf("err should be ErrServer but received error: %v", err)
+	}
+
+	pingStatus = http.StatusOK
+	health := check(http.StatusOK)
+	if health.Status != "ok" || health.Error != "" || health.LastError == "" || health.LastErrorAt == nil {
+		t.Errorf("the last error should still be reported: %+v", health)
+	}
+}
@@ -0,0 +1,133 @@
+package mopinion
+
+import (
+	"context"
+	"errors"
+)
+
+// FeedbackIterator walks through feedback page by page, one FeedbackData at a time.
+// It is not safe for concurrent use.
+//
+//	it := client.Feedback.IterateByReport(reportID, &mopinion.PaginationOptions{Limit: 100}, filters)
+//	for it.Next(ctx) {
+//		feedback := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type FeedbackIterator struct {
+	fetch   func(ctx context.Context, options *PaginationOptions) (*Feedback, *Response, error)
+	options PaginationOptions
+	where   Predicate
+
+	page    []FeedbackData
+	index   int
+	item    FeedbackData
+	meta    *Meta
+	hasMore bool
+	err     error
+}
+
+func newFeedbackIterator(options *PaginationOptions, fetch func(ctx context.Context, options *PaginationOptions) (*Feedback, *Response, error)) *FeedbackIterator {
+	it := &FeedbackIterator{fetch: fetch, hasMore: true}
+	if options != nil {
+		it.options = *options
+	}
+	if it.options.Page <= 0 {
+		it.options.Page = 1
+	}
+	return it
+}
+
+// Next advances the iterator to the next feedback, fetching the next page when needed.
+// It returns false when there is no more feedback, an error occurs or Stop is called.
+func (it *FeedbackIterator) Next(ctx context.Context) bool {
+	for {
+		for it.index >= len(it.page) {
+			if !it.hasMore || it.err != nil {
+				return false
+			}
+			if !it.fetchPage(ctx) {
+				return false
+			}
+		}
+		it.item = it.page[it.index]
+		it.index++
+		if it.where == nil || it.where(it.item) {
+			return true
+		}
+	}
+}
+
+// Where makes the iterator skip feedback that does not match the predicate. The filters
+// given when creating the iterator are applied by the API; the predicate is applied locally
+// to every item fetched, so pages are still requested in full. Calling Where again adds
+// another predicate that must match as well.
+//
+//	it := client.Feedback.IterateByReport(reportID, options, serverFilters).
+//		Where(mopinion.FieldKey("url").Contains("/checkout"))
+func (it *FeedbackIterator) Where(predicate Predicate) *FeedbackIterator {
+	if it.where != nil {
+		predicate = And(it.where, predicate)
+	}
+	it.where = predicate
+	return it
+}
+
+func (it *FeedbackIterator) fetchPage(ctx context.Context) bool {
+	options := it.options
+	feedback, _, err := it.fetch(ctx, &options)
+	if err != nil {
+		it.hasMore = false
+		// Asking for a page beyond the last one simply means there is no more feedback.
+		if !errors.Is(err, ErrPageOutOfRange) {
+			it.err = err
+		}
+		return false
+	}
+	it.meta = &feedback.Meta
+	it.page = feedback.Data
+	it.index = 0
+	it.hasMore = feedback.Meta.HasMore && len(feedback.Data) > 0
+	it.options.Page++
+	return true
+}
+
+// Item returns the current feedback.
+func (it *FeedbackIterator) Item() FeedbackData {
+	return it.item
+}
+
+// Meta returns the meta information of the last page fetched, or nil before the first one.
+func (it *FeedbackIterator) Meta() *Meta {
+	return it.meta
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *FeedbackIterator) Err() error {
+	return it.err
+}
+
+// Stop ends the iteration early; Next returns false afterwards.
+func (it *FeedbackIterator) Stop() {
+	it.hasMore = false
+	it.page = nil
+	it.index = 0
+}
+
+// IterateByDataset returns an iterator over all feedback of the given dataset. The options
+// set the first page, the page size and the sort order; the filters apply to every page.
+func (s *FeedbackService) IterateByDataset(datasetID int, options *PaginationOptions, filters *FilterCollection) *FeedbackIterator {
+	return newFeedbackIterator(options, func(ctx context.Context, options *PaginationOptions) (*Feedback, *Response, error) {
+		return s.GetByDataset(ctx, datasetID, options, filters)
+	})
+}
+
+// IterateByReport returns an iterator over all feedback of the given report. The options
+// set the first page, the page size and the sort order; the filters apply to every page.
+func (s *FeedbackService) IterateByReport(reportID int, options *PaginationOptions, filters *FilterCollection) *FeedbackIterator {
+	return newFeedbackIterator(options, func(ctx context.Context, options *PaginationOptions) (*Feedback, *Response, error) {
+		return s.GetByReport(ctx, reportID, options, filters)
+	})
+}
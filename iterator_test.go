@@ -0,0 +1,133 @@
+package mopinion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// feedbackPages serves total feedback items, limit per page. Pages beyond the last one
+// return error code 19, like the Mopinion API. If hasMore is set, every page claims there is more.
+func feedbackPages(t *testing.T, total int, hasMore bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("filter[>>date]") != "2019-10-01" {
+			t.Errorf("filters should be sent with every page, but got query: %s", r.URL.RawQuery)
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		start := (page - 1) * limit
+		if start >= total {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"status": 404, "error_code": 19, "title": "There are no results for the page number you requested"}`)
+			return
+		}
+		feedback := Feedback{Meta: Meta{Code: 200, Total: total, HasMore: hasMore || start+limit < total}}
+		for id := start + 1; id <= start+limit && id <= total; id++ {
+			feedback.Data = append(feedback.Data, FeedbackData{ID: id})
+		}
+		feedback.Meta.Count = len(feedback.Data)
+		json.NewEncoder(w).Encode(feedback)
+	}
+}
+
+func collectFeedbackIDs(it *FeedbackIterator) []int {
+	var ids []int
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Item().ID)
+	}
+	return ids
+}
+
+func TestFeedbackIterator(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/datasets/2/feedback", feedbackPages(t, 5, false))
+
+	it := client.Feedback.IterateByDataset(2, &PaginationOptions{Limit: 2}, filterCollection)
+	ids := collectFeedbackIDs(it)
+	if err := it.Err(); err != nil {
+		t.Errorf("iterator should not return an error: %s", err)
+	}
+	if expected := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(expected, ids) {
+		t.Errorf("expected feedback ids: %v but got: %v", expected, ids)
+	}
+	if meta := it.Meta(); meta == nil || meta.Total != 5 {
+		t.Errorf("expected meta of the last page but got: %+v", meta)
+	}
+}
+
+func TestFeedbackIteratorPageOutOfRange(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/1/feedback", feedbackPages(t, 4, true))
+
+	it := client.Feedback.IterateByReport(1, &PaginationOptions{Page: 2, Limit: 2}, filterCollection)
+	ids := collectFeedbackIDs(it)
+	if err := it.Err(); err != nil {
+		t.Errorf("iterator should stop cleanly when the page is out of range, but got: %s", err)
+	}
+	if expected := []int{3, 4}; !reflect.DeepEqual(expected, ids) {
+		t.Errorf("expected feedback ids: %v but got: %v", expected, ids)
+	}
+}
+
+func TestFeedbackIteratorError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	pages := feedbackPages(t, 10, false)
+	mux.HandleFunc("/reports/1/feedback", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"status": 404, "error_code": 8, "title": "Report not found"}`)
+			return
+		}
+		pages(w, r)
+	})
+
+	it := client.Feedback.IterateByReport(1, &PaginationOptions{Limit: 3}, filterCollection)
+	ids := collectFeedbackIDs(it)
+	if !errors.Is(it.Err(), ErrReportNotFound) {
+		t.Errorf("iterator should return ErrReportNotFound but got: %v", it.Err())
+	}
+	if expected := []int{1, 2, 3}; !reflect.DeepEqual(expected, ids) {
+		t.Errorf("expected feedback ids: %v but got: %v", expected, ids)
+	}
+	if it.Next(context.Background()) {
+		t.Errorf("iterator should not continue after an error")
+	}
+}
+
+func TestFeedbackIteratorStop(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	requests := 0
+	pages := feedbackPages(t, 10, false)
+	mux.HandleFunc("/reports/1/feedback", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		pages(w, r)
+	})
+
+	it := client.Feedback.IterateByReport(1, &PaginationOptions{Limit: 3}, filterCollection)
+	var ids []int
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Item().ID)
+		if len(ids) == 4 {
+			it.Stop()
+		}
+	}
+	if expected := []int{1, 2, 3, 4}; !reflect.DeepEqual(expected, ids) {
+		t.Errorf("expected feedback ids: %v but got: %v", expected, ids)
+	}
+	if requests != 2 {
+		t.Errorf("expected %d requests but got: %d", 2, requests)
+	}
+}
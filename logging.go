@@ -0,0 +1,133 @@
+package mopinion
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// Logger records what the client sends and receives. Arguments after the message are
+// alternating keys and values, so a *slog.Logger from log/slog can be used as it is.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// WithLogger makes the client log every request with its method, url, status,
+// Mopinion error code, latency and body sizes. Successful requests are logged at
+// debug level, failed ones at error level. Credentials are never logged.
+func WithLogger(logger Logger) Option {
+	return func(c *Client) error {
+		if logger == nil {
+			return fmt.Errorf("logger cannot be nil")
+		}
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithBodyDump makes the logger also record request headers and request and response
+// bodies, for troubleshooting. The x-auth-token and Authorization headers, the private
+// key and the token are redacted.
+func WithBodyDump() Option {
+	return func(c *Client) error {
+		c.dumpBodies = true
+		return nil
+	}
+}
+
+// loggingMiddleware logs the outcome of every call.
+func (c *Client) loggingMiddleware(next Handler) Handler {
+	return func(ctx context.Context, call *Call) (*Response, error) {
+		start := time.Now()
+		resp, err := next(ctx, call)
+
+		keysAndValues := []interface{}{
+			"method", call.Method,
+			"url", call.Request.URL.String(),
+			"latency", time.Since(start),
+			"request_bytes", call.Request.ContentLength,
+		}
+		if resp != nil {
+			keysAndValues = append(keysAndValues,
+				"status", resp.StatusCode,
+				"response_bytes", resp.bodySize,
+			)
+			if resp.RateLimitWait > 0 {
+				keysAndValues = append(keysAndValues, "rate_limit_wait", resp.RateLimitWait)
+			}
+		}
+		if code := errorCode(err); code != 0 {
+			keysAndValues = append(keysAndValues, "error_code", int(code))
+		}
+		if c.dumpBodies {
+			keysAndValues = append(keysAndValues,
+				"request_headers", redactHeaders(call.Request.Header),
+				"request_body", c.redact(requestBody(call.Request)),
+			)
+			if resp != nil && resp.body != nil {
+				body := resp.body.String()
+				// The token response carries the token itself.
+				if call.Path == "token" && err == nil {
+					body = redacted
+				}
+				keysAndValues = append(keysAndValues, "response_body", c.redact(body))
+			}
+		}
+
+		if err != nil {
+			c.logger.Error("mopinion request failed", append(keysAndValues, "error", c.redact(err.Error()))...)
+		} else {
+			c.logger.Debug("mopinion request", keysAndValues...)
+		}
+		return resp, err
+	}
+}
+
+// redact removes the private key and the current token from s.
+func (c *Client) redact(s string) string {
+	_, privateKey := c.keys()
+	secrets := []string{privateKey}
+	if token := c.getToken(); token != nil {
+		secrets = append(secrets, token.Token)
+	}
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.Replace(s, secret, redacted, -1)
+		}
+	}
+	return s
+}
+
+// redactHeaders returns a copy of the headers with the credentials redacted.
+func redactHeaders(header http.Header) http.Header {
+	h := make(http.Header, len(header))
+	for k, v := range header {
+		switch http.CanonicalHeaderKey(k) {
+		case "X-Auth-Token", "Authorization":
+			h[k] = []string{redacted}
+		default:
+			h[k] = v
+		}
+	}
+	return h
+}
+
+// requestBody returns the body of the request without consuming it.
+func requestBody(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	data, _ := ioutil.ReadAll(body)
+	return string(data)
+}
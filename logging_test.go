@@ -0,0 +1,135 @@
+package mopinion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type logEntry struct {
+	level         string
+	msg           string
+	keysAndValues map[string]interface{}
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) log(level, msg string, keysAndValues []interface{}) {
+	entry := logEntry{level: level, msg: msg, keysAndValues: map[string]interface{}{}}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		entry.keysAndValues[keysAndValues[i].(string)] = keysAndValues[i+1]
+	}
+	l.mu.Lock()
+	l.entries = append(l.entries, entry)
+	l.mu.Unlock()
+}
+
+func (l *recordingLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.log("debug", msg, keysAndValues)
+}
+
+func (l *recordingLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.log("error", msg, keysAndValues)
+}
+
+func newLoggingClient(t *testing.T, options ...Option) (*Client, *http.ServeMux, *recordingLogger, func()) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"token":"secrettoken"}`)
+	})
+	server := httptest.NewServer(mux)
+
+	logger := &recordingLogger{}
+	options = append([]Option{WithBaseURL(server.URL + "/"), WithLogger(logger)}, options...)
+	client, err := NewClient(NewBasicCredentialProvider("publickey", "privatekey"), options...)
+	if err != nil {
+		t.Fatalf("NewClient should not return an error: %s", err)
+	}
+	return client, mux, logger, server.Close
+}
+
+func TestLogger(t *testing.T) {
+	client, mux, logger, teardown := newLoggingClient(t)
+	defer teardown()
+
+	mux.HandleFunc("/reports/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"status": 404, "error_code": 8, "title": "Report not found"}`)
+	})
+
+	client.Reports.Get(context.Background(), 1)
+
+	if len(logger.entries) != 2 {
+		t.Fatalf("expected %d log entries but got: %d", 2, len(logger.entries))
+	}
+	token, report := logger.entries[0], logger.entries[1]
+	if token.level != "debug" || token.keysAndValues["status"] != http.StatusOK {
+		t.Errorf("unexpected log entry for token request: %+v", token)
+	}
+	if report.level != "error" {
+		t.Errorf("expected level: %v but got: %v", "error", report.level)
+	}
+	for key, expected := range map[string]interface{}{
+		"method":     "GET",
+		"status":     http.StatusNotFound,
+		"error_code": int(ErrorCodeReportNotFound),
+	} {
+		if value := report.keysAndValues[key]; value != expected {
+			t.Errorf("expected %s: %v but got: %v", key, expected, value)
+		}
+	}
+	if size, _ := report.keysAndValues["response_bytes"].(int64); size == 0 {
+		t.Errorf("response size should be logged")
+	}
+	if _, ok := report.keysAndValues["latency"]; !ok {
+		t.Errorf("latency should be logged")
+	}
+	if _, ok := report.keysAndValues["response_body"]; ok {
+		t.Errorf("response body should not be logged unless body dumps are enabled")
+	}
+}
+
+func TestLoggerBodyDumpRedaction(t *testing.T) {
+	client, mux, logger, teardown := newLoggingClient(t, WithBodyDump())
+	defer teardown()
+
+	mux.HandleFunc("/reports", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1, "name": "report name"}`)
+	})
+
+	if _, _, err := client.Reports.Add(context.Background(), &Report{Name: "report name"}); err != nil {
+		t.Fatalf("reports API should not return an error: %s", err)
+	}
+
+	if len(logger.entries) != 2 {
+		t.Fatalf("expected %d log entries but got: %d", 2, len(logger.entries))
+	}
+	for _, entry := range logger.entries {
+		dump := fmt.Sprint(entry.keysAndValues)
+		for _, secret := range []string{"privatekey", "secrettoken", "Basic "} {
+			if strings.Contains(dump, secret) {
+				t.Errorf("log entry should not contain %q: %s", secret, dump)
+			}
+		}
+		headers := entry.keysAndValues["request_headers"].(http.Header)
+		for _, name := range []string{"Authorization", "X-Auth-Token"} {
+			if value := headers.Get(name); value != "" && value != redacted {
+				t.Errorf("%s header should be redacted but got: %v", name, value)
+			}
+		}
+	}
+	report := logger.entries[1].keysAndValues
+	if body := report["request_body"]; !strings.Contains(body.(string), "report name") {
+		t.Errorf("request body should be logged but got: %v", body)
+	}
+	if body := report["response_body"]; body != `{"id": 1, "name": "report name"}` {
+		t.Errorf("response body should be logged but got: %v", body)
+	}
+}
@@ -0,0 +1,108 @@
+package mopinion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrNoPage is returned when following a link to a page that does not exist,
+// e.g. the next page of the last page.
+var ErrNoPage = errors.New("mopinion: there is no such page")
+
+// NextURL returns the url of the next page, if there is one.
+func (m Meta) NextURL() (*url.URL, bool) {
+	return parseLink(m.Next)
+}
+
+// PreviousURL returns the url of the previous page, if there is one.
+func (m Meta) PreviousURL() (*url.URL, bool) {
+	return parseLink(m.Previous)
+}
+
+// parseLink parses the value of a next or previous link,
+// which is either false or a string holding the url.
+func parseLink(link interface{}) (*url.URL, bool) {
+	s, ok := link.(string)
+	if !ok || s == "" {
+		return nil, false
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, false
+	}
+	return u, true
+}
+
+// linkPath turns a link returned by the API into a url relative to BaseURL, so it can be
+// passed to NewRequest. Links to other hosts are refused, since the request would be signed.
+func (c *Client) linkPath(link *url.URL) (string, error) {
+	if link.IsAbs() && (link.Scheme != c.BaseURL.Scheme || link.Host != c.BaseURL.Host) {
+		return "", fmt.Errorf("link %q does not point to %q", link, c.BaseURL)
+	}
+	path := link.Path
+	if strings.HasPrefix(path, c.BaseURL.Path) {
+		path = strings.TrimPrefix(path, c.BaseURL.Path)
+	} else {
+		path = strings.TrimPrefix(path, "/")
+	}
+	u := &url.URL{Path: path, RawQuery: link.RawQuery}
+	return u.String(), nil
+}
+
+// followLink returns the path of the next or previous link in meta.
+func (c *Client) followLink(meta *Meta, next bool) (string, error) {
+	if meta == nil {
+		return "", ErrNoPage
+	}
+	link, ok := meta.PreviousURL()
+	if next {
+		link, ok = meta.NextURL()
+	}
+	if !ok {
+		return "", ErrNoPage
+	}
+	return c.linkPath(link)
+}
+
+// FollowNext returns the feedback page after the one the meta belongs to,
+// or ErrNoPage if it is the last one.
+func (s *FeedbackService) FollowNext(ctx context.Context, meta *Meta) (*Feedback, *Response, error) {
+	u, err := s.client.followLink(meta, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.get(ctx, u)
+}
+
+// FollowPrevious returns the feedback page before the one the meta belongs to,
+// or ErrNoPage if it is the first one.
+func (s *FeedbackService) FollowPrevious(ctx context.Context, meta *Meta) (*Feedback, *Response, error) {
+	u, err := s.client.followLink(meta, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.get(ctx, u)
+}
+
+// FollowNext returns the fields page after the one the meta belongs to,
+// or ErrNoPage if it is the last one.
+func (s *FieldsService) FollowNext(ctx context.Context, meta *Meta) (*Fields, *Response, error) {
+	u, err := s.client.followLink(meta, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.get(ctx, u)
+}
+
+// FollowPrevious returns the fields page before the one the meta belongs to,
+// or ErrNoPage if it is the first one.
+func (s *FieldsService) FollowPrevious(ctx context.Context, meta *Meta) (*Fields, *Response, error) {
+	u, err := s.client.followLink(meta, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.get(ctx, u)
+}
@@ -0,0 +1,106 @@
+package mopinion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestMetaURLs(t *testing.T) {
+	var meta Meta
+	err := json.Unmarshal([]byte(`{"next": "https://api.mopinion.com/reports/1/feedback?page=3", "previous": false}`), &meta)
+	if err != nil {
+		t.Fatalf("unmarshaling should not return an error: %s", err)
+	}
+
+	next, ok := meta.NextURL()
+	if !ok || next.String() != "https://api.mopinion.com/reports/1/feedback?page=3" {
+		t.Errorf("expected next url but got: %v, %v", next, ok)
+	}
+	if previous, ok := meta.PreviousURL(); ok {
+		t.Errorf("expected no previous url but got: %v", previous)
+	}
+	if next, ok := (Meta{}).NextURL(); ok {
+		t.Errorf("expected no next url but got: %v", next)
+	}
+}
+
+func TestFeedbackFollowLinks(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/1/feedback", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprintf(w, `{"data": [{"id": 1}], "_meta": {"has_more": true, "previous": false, "next": "%s/reports/1/feedback?page=2"}}`, serverURL)
+		case "2":
+			fmt.Fprint(w, `{"data": [{"id": 2}], "_meta": {"has_more": false, "previous": "/reports/1/feedback?page=1", "next": false}}`)
+		default:
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+	})
+
+	first, _, err := client.Feedback.GetByReport(context.Background(), 1, &PaginationOptions{Page: 1}, nil)
+	if err != nil {
+		t.Fatalf("feedback API should not return an error: %s", err)
+	}
+	if _, _, err := client.Feedback.FollowPrevious(context.Background(), &first.Meta); err != ErrNoPage {
+		t.Errorf("expected ErrNoPage but got: %v", err)
+	}
+
+	second, _, err := client.Feedback.FollowNext(context.Background(), &first.Meta)
+	if err != nil {
+		t.Fatalf("following the next link should not return an error: %s", err)
+	}
+	if second.Data[0].ID != 2 {
+		t.Errorf("expected feedback id: %v but got: %v", 2, second.Data[0].ID)
+	}
+	if _, _, err := client.Feedback.FollowNext(context.Background(), &second.Meta); err != ErrNoPage {
+		t.Errorf("expected ErrNoPage but got: %v", err)
+	}
+
+	previous, _, err := client.Feedback.FollowPrevious(context.Background(), &second.Meta)
+	if err != nil {
+		t.Fatalf("following the previous link should not return an error: %s", err)
+	}
+	if previous.Data[0].ID != 1 {
+		t.Errorf("expected feedback id: %v but got: %v", 1, previous.Data[0].ID)
+	}
+}
+
+func TestFieldsFollowNext(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/datasets/2/fields", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `{"data": [{"key": "second"}], "_meta": {"next": false}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": [{"key": "first"}], "_meta": {"next": "datasets/2/fields?page=2"}}`)
+	})
+
+	fields, _, err := client.Fields.GetByDataset(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("fields API should not return an error: %s", err)
+	}
+	next, _, err := client.Fields.FollowNext(context.Background(), &fields.Meta)
+	if err != nil {
+		t.Fatalf("following the next link should not return an error: %s", err)
+	}
+	if next.Data[0].Key != "second" {
+		t.Errorf("expected field key: %v but got: %v", "second", next.Data[0].Key)
+	}
+}
+
+func TestFollowLinkToOtherHost(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	meta := &Meta{Next: "https://example.com/reports/1/feedback?page=2"}
+	if _, _, err := client.Feedback.FollowNext(context.Background(), meta); err == nil {
+		t.Errorf("following a link to another host should return an error")
+	}
+}
@@ -0,0 +1,50 @@
+package mopinion
+
+import (
+	"context"
+	"net/http"
+)
+
+// Call describes a single request to the Mopinion API going through the middleware chain.
+type Call struct {
+	// Method is the HTTP method of the request.
+	Method string
+
+	// Path is the path relative to BaseURL, e.g. "reports/1".
+	Path string
+
+	// Request is the request about to be sent. It is already signed if the route
+	// requires authentication. Headers added to it are sent along, but changing
+	// the path or the body invalidates the signature.
+	Request *http.Request
+}
+
+// Handler sends a call to the Mopinion API and returns the response.
+// The error is typed the same way as the errors returned by CheckResponse.
+type Handler func(ctx context.Context, call *Call) (*Response, error)
+
+// Middleware wraps a Handler to run code before and after every call.
+type Middleware func(next Handler) Handler
+
+// Use adds middleware wrapping every request the client sends, including retries
+// and the requests repeated after a token refresh. Middleware added first runs outermost.
+func (c *Client) Use(middleware ...Middleware) {
+	c.middlewareMu.Lock()
+	defer c.middlewareMu.Unlock()
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// roundTrip sends the request through the middleware chain.
+func (c *Client) roundTrip(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
+	c.middlewareMu.RLock()
+	middleware := c.middleware
+	c.middlewareMu.RUnlock()
+
+	handler := Handler(func(ctx context.Context, call *Call) (*Response, error) {
+		return c.do(ctx, call.Request, v)
+	})
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler(ctx, &Call{Method: req.Method, Path: c.relativePath(req.URL), Request: req})
+}
@@ -0,0 +1,63 @@
+package mopinion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Request-Id") != "id" {
+			t.Errorf("expected X-Request-Id header to be set by middleware")
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"status": 404, "error_code": 8, "title": "Report not found"}`)
+	})
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, call *Call) (*Response, error) {
+				order = append(order, name+" before "+call.Method+" "+call.Path)
+				resp, err := next(ctx, call)
+				if errors.Is(err, ErrReportNotFound) && resp.StatusCode == http.StatusNotFound {
+					order = append(order, name+" after report not found")
+				}
+				return resp, err
+			}
+		}
+	}
+	client.Use(trace("outer"), trace("inner"))
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (*Response, error) {
+			if call.Path != "token" && call.Request.Header.Get("x-auth-token") == "" {
+				t.Errorf("middleware should receive a signed request")
+			}
+			call.Request.Header.Set("X-Request-Id", "id")
+			return next(ctx, call)
+		}
+	})
+
+	if _, _, err := client.Reports.Get(context.Background(), 1); err == nil {
+		t.Errorf("reports API should return an error")
+	}
+
+	expected := []string{
+		"outer before GET token",
+		"inner before GET token",
+		"outer before GET reports/1",
+		"inner before GET reports/1",
+		"inner after report not found",
+		"outer after report not found",
+	}
+	if !reflect.DeepEqual(expected, order) {
+		t.Errorf("expected order: %v but got: %v", expected, order)
+	}
+}
@@ -7,8 +7,8 @@ type Meta struct {
 	Count    int
 	HasMore  bool `json:"has_more"`
 	Message  string
-	Next     interface{} // boolean false, or a string with a url
-	Previous interface{}
+	Next     interface{} // boolean false, or a string with a url, see NextURL
+	Previous interface{} // boolean false, or a string with a url, see PreviousURL
 	Total    int
 }
 
@@ -16,6 +16,9 @@ type Meta struct {
 // It is used to generate a hmac signature.
 type Token struct {
 	Token string
+
+	// publicKey is the key the token was issued for.
+	publicKey string
 }
 
 // Account is a struct which reflects to mopinion Account resource.
@@ -23,7 +26,7 @@ type Account struct {
 	Meta          Meta `json:"_meta,omitempty"`
 	Name          string
 	Package       string
-	EndDate       string
+	EndDate       DateTime
 	NumberUsers   int `json:"number_users"`
 	NumberCharts  int `json:"number_charts"`
 	NumberForms   int `json:"number_forms"`
@@ -45,12 +48,12 @@ type Deployment struct {
 
 // Report is a struct which reflects to mopinion Report resource.
 type Report struct {
-	Meta        *Meta  `json:"_meta,omitempty"`
-	ID          int    `json:"id,omitempty"`
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
-	Language    string `json:"language,omitempty"`
-	Created     string `json:"created,omitempty"`
+	Meta        *Meta     `json:"_meta,omitempty"`
+	ID          int       `json:"id,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Language    string    `json:"language,omitempty"`
+	Created     *DateTime `json:"created,omitempty"`
 	Datasets    []Dataset
 }
 
@@ -97,7 +100,7 @@ type Feedback struct {
 
 // FeedbackData is a struct which reflects to mopinion FeedbackData resource.
 type FeedbackData struct {
-	Created   string
+	Created   DateTime
 	DatasetID int `json:"dataset_id"`
 	ID        int
 	ReportID  int `json:"report_id"`
@@ -3,10 +3,8 @@ package mopinion
 import (
 	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -14,6 +12,8 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -23,48 +23,65 @@ const (
 	userAgent      = "mopinion-go-client"
 )
 
-const (
-	// API error codes.
-	// https://developer.mopinion.com/api/error-codes/
-	unknown ErrorCode = iota + 1
-	server
-	publicKeyNotFound
-	invalidToken
-	invalidJSON
-	routeNotFound
-	reportIDNotSet
-	reportNotFound
-	failedToUpdateResource
-	notAuthorized
-	maxReportsReached
-	invalidRequest
-	datasetIDNotSet
-	datasetNotFound
-	maxDatasetsReached
-	failedToCreateResource
-	noDeploymentCode
-	notAuthenticated
-)
-
-// ErrorCode represented
-type ErrorCode int
-
 // Client represents the Mopinion client.
+//
+// A Client is safe for concurrent use by multiple goroutines. The token is shared
+// by all goroutines and refreshed at most once at a time; goroutines needing a new
+// token wait for the refresh in flight instead of starting their own. The exported
+// fields must not be modified once the Client is in use.
 type Client struct {
 	// Client is used to communicate with the mopinion api.
 	client *http.Client
 
+	// credentialProvider is consulted again to pick up rotated keys.
+	credentialProvider CredentialProvider
+
+	// credentialRefreshInterval is how often the keys are read again, if positive.
+	credentialRefreshInterval time.Duration
+
 	// publicKey is a key used together with the private key to get a token.
 	// It has nothing to do with public-key cryptography.
-	// It serves like an account id.
+	// It serves like an account id. It is guarded by tokenMu.
 	publicKey string
 
-	// privateKey is a key for the public key above.
+	// privateKey is a key for the public key above. It is guarded by tokenMu.
 	privateKey string
 
+	// keysLoadedAt is when the keys were last read from the credential provider,
+	// keysStale is set when the provider reported a change. Both are guarded by tokenMu.
+	keysLoadedAt time.Time
+	keysStale    bool
+
 	// token is retrieved through token api to be used for
-	// generating HMAC signature.
-	token *Token
+	// generating HMAC signature. It is guarded by tokenMu.
+	token   *Token
+	tokenMu sync.RWMutex
+
+	// refresh is the token request in flight, if any. It is guarded by refreshMu.
+	refresh   *tokenRefresh
+	refreshMu sync.Mutex
+
+	// dateLocation is the location dates without a time zone are parsed in, if set.
+	dateLocation *time.Location
+
+	// timeout is the time limit set through WithTimeout.
+	timeout time.Duration
+
+	// tokenStore keeps tokens between clients, if set.
+	tokenStore TokenStore
+
+	// rateLimiter paces the requests sent, if set.
+	rateLimiter RateLimiter
+
+	// logger records every request sent, if set.
+	logger Logger
+
+	// dumpBodies makes the logger record request and response bodies as well.
+	dumpBodies bool
+
+	// middleware wraps every request sent. It is guarded by middlewareMu.
+	middleware   []Middleware
+	middlewareMu sync.RWMutex
 
 	// BaseURL holds the url for the mopinion api.
 	BaseURL *url.URL
@@ -72,8 +89,13 @@ type Client struct {
 	// UserAgent holds the agent name while communicating with mopinion api.
 	UserAgent string
 
+	// RetryPolicy decides whether failed requests are sent again.
+	// Requests are not retried if it is nil.
+	RetryPolicy RetryPolicy
+
 	// Services used for talking to different parts of the Mopinion API.
 	Token       TokenInterface
+	Ping        PingInterface
 	Account     AccountInterface
 	Deployments DeploymentsInterface
 	Datasets    DatasetsInterface
@@ -111,25 +133,41 @@ func NewBasicCredentialProvider(publicKey, privateKey string) CredentialProvider
 	}
 }
 
-// NewClient returns a new Mopinion API client.
-func NewClient(httpClient *http.Client, credentialProvider CredentialProvider) (*Client, error) {
-	if httpClient == nil {
-		httpClient = http.DefaultClient
-	}
+// NewClient returns a new Mopinion API client configured by the given options.
+func NewClient(credentialProvider CredentialProvider, options ...Option) (*Client, error) {
 	if credentialProvider == nil {
 		return nil, fmt.Errorf("credentialProvider cannot be nil")
 	}
 
 	baseURL, _ := url.Parse(defaultBaseURL)
-	c := &Client{client: httpClient, BaseURL: baseURL, UserAgent: userAgent}
+	c := &Client{client: http.DefaultClient, BaseURL: baseURL, UserAgent: userAgent}
+	for _, option := range options {
+		if err := option(c); err != nil {
+			return nil, fmt.Errorf("invalid option: %s", err)
+		}
+	}
+	if c.logger != nil {
+		c.Use(c.loggingMiddleware)
+	}
+	if c.timeout > 0 {
+		httpClient := *c.client
+		httpClient.Timeout = c.timeout
+		c.client = &httpClient
+	}
 
 	var err error
 	if c.publicKey, c.privateKey, err = credentialProvider.Keys(); err != nil {
 		return nil, fmt.Errorf("read keys: %s", err)
 	}
+	c.credentialProvider = credentialProvider
+	c.keysLoadedAt = time.Now()
+	if c.tokenStore != nil {
+		c.loadToken()
+	}
 
 	service := service{client: c}
 	c.Token = &TokenService{service}
+	c.Ping = &PingService{service}
 	c.Account = &AccountService{service}
 	c.Deployments = &DeploymentsService{service}
 	c.Datasets = &DatasetsService{service}
@@ -140,38 +178,124 @@ func NewClient(httpClient *http.Client, credentialProvider CredentialProvider) (
 	return c, nil
 }
 
-func (c *Client) makeToken(path string, body []byte) string {
-	mac := hmac.New(sha256.New, []byte(string(c.token.Token)))
-	mac.Write([]byte(path + "|"))
-	mac.Write(body)
-	sig := mac.Sum(nil)
-	return base64.StdEncoding.EncodeToString([]byte(c.publicKey + ":" + fmt.Sprintf("%x", sig)))
+func (c *Client) makeToken(token *Token, path string, body []byte) string {
+	return c.signer(token).Signature(path, body)
+}
+
+// signer returns a Signer for the token. It signs with the public key the token was
+// issued for, which may differ from the current one while the keys are being rotated.
+func (c *Client) signer(token *Token) Signer {
+	publicKey := token.publicKey
+	if publicKey == "" {
+		publicKey, _ = c.keys()
+	}
+	return Signer{PublicKey: publicKey, Token: token.Token}
 }
 
 // AddAutheticationToken adds an x-auth-token.
 func (c *Client) AddAutheticationToken(req *http.Request) error {
-	if c.token == nil {
+	token := c.getToken()
+	if token == nil {
 		return fmt.Errorf("token cannot be nil. Get a token first")
 	}
+	return c.signRequest(req, token)
+}
 
-	path := req.URL.Path
-	// Relative paths may omit leading slash.
-	if !strings.HasPrefix(req.URL.Path, "/") {
-		path = fmt.Sprintf("/%s", path)
-	}
-	var body []byte
-	if req.Body != nil {
-		body, _ = ioutil.ReadAll(req.Body)
+func (c *Client) signRequest(req *http.Request, token *Token) error {
+	return c.signer(token).Sign(req)
+}
+
+// DateLocation returns the location dates sent without a time zone are parsed in,
+// as set with WithDateLocation.
+func (c *Client) DateLocation() *time.Location {
+	if c.dateLocation == nil {
+		return time.UTC
 	}
-	req.Header.Add("x-auth-token", c.makeToken(path, body))
-	// Restore the io.ReadCloser to its original state.
-	req.Body = ioutil.NopCloser(bytes.NewBuffer(body))
-	return nil
+	return c.dateLocation
 }
 
 // SetToken sets a token.
 func (c *Client) SetToken(token *Token) {
+	c.tokenMu.Lock()
 	c.token = token
+	c.tokenMu.Unlock()
+}
+
+func (c *Client) getToken() *Token {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	return c.token
+}
+
+// keys returns the current public and private keys respectively.
+func (c *Client) keys() (string, string) {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	return c.publicKey, c.privateKey
+}
+
+// ensureToken returns the current token, fetching one if the client has none yet
+// or the keys have to be read again.
+func (c *Client) ensureToken(ctx context.Context) (*Token, error) {
+	token := c.getToken()
+	if token != nil && !c.keysDue() {
+		return token, nil
+	}
+	return c.refreshToken(ctx, token, token == nil)
+}
+
+// tokenRefresh is a token request shared by all goroutines waiting for a new token.
+type tokenRefresh struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+// refreshToken fetches a new token to replace the stale one, which is rejected
+// by the API or belongs to keys that may have been rotated. If another goroutine
+// has already replaced the stale token in the meantime, its token is returned instead.
+// Concurrent callers share a single token request.
+func (c *Client) refreshToken(ctx context.Context, stale *Token, rejected bool) (*Token, error) {
+	for {
+		c.refreshMu.Lock()
+		if token := c.getToken(); token != nil && token != stale {
+			c.refreshMu.Unlock()
+			return token, nil
+		}
+		if call := c.refresh; call != nil {
+			c.refreshMu.Unlock()
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			// The goroutine making the request gave up on it, but we may still try.
+			if isContextError(call.err) && ctx.Err() == nil {
+				continue
+			}
+			return call.token, call.err
+		}
+		call := &tokenRefresh{done: make(chan struct{})}
+		c.refresh = call
+		c.refreshMu.Unlock()
+
+		call.token, call.err = c.renewToken(ctx, stale, rejected)
+
+		c.refreshMu.Lock()
+		c.refresh = nil
+		c.refreshMu.Unlock()
+		close(call.done)
+		return call.token, call.err
+	}
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// relativePath returns the path of u relative to BaseURL, e.g. "token" or "reports/1".
+func (c *Client) relativePath(u *url.URL) string {
+	return strings.TrimPrefix(u.Path, c.BaseURL.Path)
 }
 
 // IsAuthenticationRequired checks if an auth token needs to be passed along with the given relative URL.
@@ -216,8 +340,10 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 		req.Header.Set("User-Agent", c.UserAgent)
 	}
 
-	if c.IsAuthenticationRequired(method, urlStr) {
-		if err := c.AddAutheticationToken(req); err != nil {
+	// The request is signed right away when a token is available. Otherwise
+	// Do takes care of getting a token and signing the request.
+	if token := c.getToken(); token != nil && c.IsAuthenticationRequired(method, urlStr) {
+		if err := c.signRequest(req, token); err != nil {
 			return nil, err
 		}
 	}
@@ -226,8 +352,91 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 }
 
 // Do makes a request to mopinion API and returns the response.
+// Requests requiring authentication are signed with the current token. If the
+// client has no token yet, one is retrieved first. When the API rejects the
+// token, a new token is retrieved and the request is signed and sent once more.
+// Failed requests are retried as long as the RetryPolicy allows it.
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
-	req.WithContext(ctx)
+	for attempt := 1; ; attempt++ {
+		response, err := c.send(ctx, req, v)
+		if err == nil || c.RetryPolicy == nil || ctx.Err() != nil {
+			return response, err
+		}
+		delay, ok := c.RetryPolicy.Retry(attempt, req, response, err)
+		if !ok {
+			return response, err
+		}
+		// Give up right away if the context would expire before the next attempt.
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return response, err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response, err
+		case <-timer.C:
+		}
+		if err := rewindBody(req); err != nil {
+			return response, err
+		}
+	}
+}
+
+// send makes a single request, including the token handling described in Do.
+func (c *Client) send(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
+	if !c.IsAuthenticationRequired(req.Method, c.relativePath(req.URL)) {
+		return c.roundTrip(ctx, req, v)
+	}
+
+	token, err := c.ensureToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.resign(req, token); err != nil {
+		return nil, err
+	}
+	response, err := c.roundTrip(ctx, req, v)
+	if !isTokenError(err) {
+		return response, err
+	}
+
+	if token, err = c.refreshToken(ctx, token, true); err != nil {
+		return response, err
+	}
+	if err := c.resign(req, token); err != nil {
+		return nil, err
+	}
+	return c.roundTrip(ctx, req, v)
+}
+
+// resign rewinds the request body, if possible, and signs the request with the given token.
+func (c *Client) resign(req *http.Request, token *Token) error {
+	if err := rewindBody(req); err != nil {
+		return err
+	}
+	return c.signRequest(req, token)
+}
+
+// rewindBody restores the request body so the request can be sent again.
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Errorf("rewind request body: %s", err)
+	}
+	req.Body = body
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
+	waited, err := c.waitRateLimit(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
 	resp, err := c.client.Do(req)
 	if err != nil {
 		// If we got an error, and the context has been canceled,
@@ -241,6 +450,21 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 	}
 	defer resp.Body.Close()
 	response := newResponse(resp)
+	response.RateLimitWait = waited
+
+	// Count the bytes read from the body and keep a copy of it for body dumps.
+	counter := &countingReader{r: resp.Body}
+	var body io.Reader = counter
+	if c.dumpBodies {
+		response.body = new(bytes.Buffer)
+		body = io.TeeReader(counter, response.body)
+	}
+	resp.Body = ioutil.NopCloser(body)
+	defer func() {
+		io.Copy(ioutil.Discard, resp.Body)
+		response.bodySize = counter.n
+	}()
+
 	err = CheckResponse(resp)
 
 	if err != nil {
@@ -255,11 +479,25 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 			if err == io.EOF {
 				err = nil // ignore EOF errors caused by empty response body
 			}
+			if err == nil && c.dateLocation != nil && c.dateLocation != time.UTC {
+				setDateLocation(reflect.ValueOf(v), c.dateLocation)
+			}
 		}
 	}
 	return response, err
 }
 
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
 // PaginationOptions holds info for pagination.
 type PaginationOptions struct {
 	Page  int    `url:"page,omitempty"`
@@ -299,11 +537,11 @@ func CheckResponse(r *http.Response) error {
 		json.Unmarshal(data, errorResponse)
 	}
 	switch {
-	case r.StatusCode == http.StatusUnauthorized && ErrorCode(errorResponse.ErrorCode) == notAuthenticated:
+	case r.StatusCode == http.StatusUnauthorized && ErrorCode(errorResponse.ErrorCode) == ErrorCodeNotAuthenticated:
 		return (*AuthenticationError)(errorResponse)
-	case ErrorCode(errorResponse.ErrorCode) == server:
+	case ErrorCode(errorResponse.ErrorCode) == ErrorCodeServer:
 		return (*ServerError)(errorResponse)
-	// TODO: other errors should be added here
+	// Any other error code can be matched with errors.Is, e.g. errors.Is(err, ErrReportNotFound).
 	default:
 		return errorResponse
 	}
@@ -314,11 +552,17 @@ type AuthenticationError ErrorResponse
 
 func (r *AuthenticationError) Error() string { return (*ErrorResponse)(r).Error() }
 
+// Unwrap returns the underlying ErrorResponse.
+func (r *AuthenticationError) Unwrap() error { return (*ErrorResponse)(r) }
+
 // ServerError is unexpected server side error, coming through Mopinion API.
 type ServerError ErrorResponse
 
 func (r *ServerError) Error() string { return "unexpected server side error. Try your request again" }
 
+// Unwrap returns the underlying ErrorResponse.
+func (r *ServerError) Unwrap() error { return (*ErrorResponse)(r) }
+
 // ErrorResponse represent the returning error struct.
 // Implements the error interface.
 type ErrorResponse struct {
@@ -336,9 +580,39 @@ func (r *ErrorResponse) Error() string {
 		r.Response.StatusCode, r.Status, r.ErrorCode, r.Title)
 }
 
+// Is reports whether the error response carries the given ErrorCode,
+// so that errors.Is(err, ErrReportNotFound) works.
+func (r *ErrorResponse) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && code == ErrorCode(r.ErrorCode)
+}
+
+// As sets target to the ErrorCode of the error response if target is an *ErrorCode.
+func (r *ErrorResponse) As(target interface{}) bool {
+	code, ok := target.(*ErrorCode)
+	if ok {
+		*code = ErrorCode(r.ErrorCode)
+	}
+	return ok
+}
+
+// Explanation returns the documented explanation of the error code.
+func (r *ErrorResponse) Explanation() string {
+	return ErrorCode(r.ErrorCode).Explanation()
+}
+
 // Response is wrapper around http.Response.
 type Response struct {
 	*http.Response
+
+	// RateLimitWait is how long the request waited for the rate limiter before it was sent.
+	RateLimitWait time.Duration
+
+	// bodySize is the number of bytes read from the response body.
+	bodySize int64
+
+	// body holds a copy of the response body if body dumps are enabled.
+	body *bytes.Buffer
 }
 
 func newResponse(r *http.Response) *Response {
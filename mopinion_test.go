@@ -3,9 +3,9 @@ package mopinion
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
-	"net/url"
 	"testing"
 )
 
@@ -19,9 +19,7 @@ func setup() (client *Client, mux *http.ServeMux, serverURL string, teardown fun
 
 	server := httptest.NewServer(mux)
 
-	client, _ = NewClient(nil, NewBasicCredentialProvider("publickey", "privatekey"))
-	url, _ := url.Parse(server.URL + "/")
-	client.BaseURL = url
+	client, _ = NewClient(NewBasicCredentialProvider("publickey", "privatekey"), WithBaseURL(server.URL+"/"))
 
 	return client, mux, server.URL, server.Close
 }
@@ -69,3 +67,61 @@ func TestErrorResponse(t *testing.T) {
 		t.Errorf("err should be an AuthenticationError but received error: %v", err)
 	}
 }
+
+func TestDoGetsTokenLazily(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-auth-token") == "" {
+			t.Errorf("x-auth-token header should be set")
+		}
+		fmt.Fprint(w, `{"name": "account name"}`)
+	})
+
+	account, _, err := client.Account.Get(context.Background())
+	if err != nil {
+		t.Fatalf("account API should not return an error: %s", err)
+	}
+	if account.Name != "account name" {
+		t.Errorf("expected account name: %v but got: %v", "account name", account.Name)
+	}
+	if token := client.getToken(); token == nil || token.Token != "token" {
+		t.Errorf("expected token: %v but got: %v", "token", token)
+	}
+}
+
+func TestDoRefreshesInvalidToken(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, _ := NewClient(NewBasicCredentialProvider("publickey", "privatekey"), WithBaseURL(server.URL+"/"))
+
+	tokenCalls := 0
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenCalls++
+		fmt.Fprintf(w, `{"token":"token%d"}`, tokenCalls)
+	})
+	mux.HandleFunc("/reports", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := client.makeToken(&Token{Token: "token2"}, "/reports", body)
+		if r.Header.Get("x-auth-token") != expected {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"status": 401, "error_code": 4, "title": "Invalid token"}`)
+			return
+		}
+		fmt.Fprint(w, string(body))
+	})
+
+	report, _, err := client.Reports.Add(context.Background(), &Report{Name: "report name"})
+	if err != nil {
+		t.Fatalf("reports API should not return an error: %s", err)
+	}
+	if report.Name != "report name" {
+		t.Errorf("expected report name: %v but got: %v", "report name", report.Name)
+	}
+	if tokenCalls != 2 {
+		t.Errorf("expected token API to be called %d times but got: %d", 2, tokenCalls)
+	}
+}
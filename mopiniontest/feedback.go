@@ -0,0 +1,226 @@
+package mopiniontest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/oylmz/mopinion"
+)
+
+// The handlers below are called with mu held.
+
+func (s *Server) getFields(matches func(field mopinion.FieldData) bool) (int, interface{}, *apiError) {
+	fields := []mopinion.FieldData{}
+	for _, field := range s.fields {
+		if matches(field) {
+			fields = append(fields, field)
+		}
+	}
+	return http.StatusOK, mopinion.Fields{Meta: meta(len(fields), len(fields), false, "", ""), Data: fields}, nil
+}
+
+// getFeedback returns a page of the matching feedback that passes the filters of the request.
+// Feedback is sorted by id, or by creation date when sort=created, in ascending order unless
+// order=desc. Pages beyond the last one return error code 19, like the API.
+func (s *Server) getFeedback(r *http.Request, matches func(feedback mopinion.FeedbackData) bool) (int, interface{}, *apiError) {
+	query := r.URL.Query()
+	page, limit := 1, DefaultLimit
+	var err error
+	if v := query.Get("page"); v != "" {
+		if page, err = strconv.Atoi(v); err != nil || page < 1 {
+			return 0, nil, newError(http.StatusBadRequest, mopinion.ErrorCodeInvalidRequest)
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 {
+			return 0, nil, newError(http.StatusBadRequest, mopinion.ErrorCodeInvalidRequest)
+		}
+	}
+	filters, apiErr := parseFilters(query)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+
+	schema := mopinion.NewFieldSchema(s.fields)
+	feedback := []mopinion.FeedbackData{}
+	for _, f := range s.feedback {
+		if matches(f) && filters.match(schema, f) {
+			feedback = append(feedback, f)
+		}
+	}
+	sortFeedback(feedback, query.Get("sort"), query.Get("order"))
+
+	total := len(feedback)
+	start := (page - 1) * limit
+	if start >= total && page > 1 {
+		return 0, nil, newError(http.StatusNotFound, mopinion.ErrorCodePageOutOfRange)
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	data := feedback[start:end]
+
+	var previous, next string
+	if page > 1 {
+		previous = s.pageURL(r, page-1)
+	}
+	if end < total {
+		next = s.pageURL(r, page+1)
+	}
+	return http.StatusOK, mopinion.Feedback{Meta: meta(len(data), total, end < total, previous, next), Data: data}, nil
+}
+
+func (s *Server) pageURL(r *http.Request, page int) string {
+	query := r.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	return fmt.Sprintf("%s%s?%s", s.URL, r.URL.Path, query.Encode())
+}
+
+func sortFeedback(feedback []mopinion.FeedbackData, by, order string) {
+	less := func(i, j int) bool { return feedback[i].ID < feedback[j].ID }
+	if by == "created" {
+		less = func(i, j int) bool {
+			a, b := feedback[i].Created.String(), feedback[j].Created.String()
+			if a == b {
+				return feedback[i].ID < feedback[j].ID
+			}
+			return a < b
+		}
+	}
+	if order == "desc" {
+		sort.SliceStable(feedback, func(i, j int) bool { return less(j, i) })
+		return
+	}
+	sort.SliceStable(feedback, less)
+}
+
+type filter struct {
+	key      mopinion.FilterKey
+	modifier mopinion.FilterModifier
+	value    string
+}
+
+type filters []filter
+
+// parseFilters reads the filter[<modifier><key>]=<value> parameters of a query.
+func parseFilters(query url.Values) (filters, *apiError) {
+	var result filters
+	for param, values := range query {
+		if !strings.HasPrefix(param, "filter[") || !strings.HasSuffix(param, "]") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(param, "filter["), "]")
+		key := strings.TrimLeft(name, "!<>")
+		f := filter{key: mopinion.FilterKey(key), modifier: mopinion.FilterModifier(name[:len(name)-len(key)])}
+		switch f.modifier {
+		case "", mopinion.Not, mopinion.Lt, mopinion.Lte, mopinion.Gt, mopinion.Gte:
+		default:
+			return nil, newError(http.StatusBadRequest, mopinion.ErrorCodeInvalidRequest)
+		}
+		switch f.key {
+		case mopinion.Date, mopinion.Rating, mopinion.Nps, mopinion.Ces, mopinion.CesInverse:
+		case mopinion.Gcr, mopinion.Tags:
+			if f.modifier != "" && f.modifier != mopinion.Not {
+				return nil, newError(http.StatusBadRequest, mopinion.ErrorCodeInvalidRequest)
+			}
+		default:
+			return nil, newError(http.StatusBadRequest, mopinion.ErrorCodeInvalidRequest)
+		}
+		for _, value := range values {
+			f.value = value
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+// match reports whether the feedback passes all filters.
+func (fs filters) match(schema *mopinion.FieldSchema, feedback mopinion.FeedbackData) bool {
+	for _, f := range fs {
+		if !f.match(schema, feedback) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f filter) match(schema *mopinion.FieldSchema, feedback mopinion.FeedbackData) bool {
+	switch f.key {
+	case mopinion.Date:
+		created := feedback.Created.String()
+		if len(created) > len(mopinion.FilterDateLayout) {
+			created = created[:len(mopinion.FilterDateLayout)]
+		}
+		return compare(strings.Compare(created, f.value), f.modifier)
+	case mopinion.Tags:
+		for _, tag := range feedback.Tags {
+			if tag == f.value {
+				return f.modifier == ""
+			}
+		}
+		return f.modifier == mopinion.Not
+	case mopinion.Gcr:
+		for _, field := range feedback.Fields {
+			if data, ok := schema.Field(field.Key); ok && data.Type == mopinion.FieldTypeGcr {
+				return compare(strings.Compare(fmt.Sprint(field.Value), f.value), f.modifier)
+			}
+		}
+		return false
+	}
+
+	want, err := strconv.ParseFloat(f.value, 64)
+	if err != nil {
+		return false
+	}
+	fieldType := string(f.key)
+	if f.key == mopinion.CesInverse {
+		fieldType = mopinion.FieldTypeCes
+	}
+	for _, field := range feedback.Fields {
+		data, ok := schema.Field(field.Key)
+		if !ok || data.Type != fieldType {
+			continue
+		}
+		value, err := schema.Value(field).AsInt()
+		if err != nil {
+			continue
+		}
+		got := float64(value)
+		// The inverse CES turns the 1 to 5 scale around.
+		if f.key == mopinion.CesInverse {
+			got = 6 - got
+		}
+		switch {
+		case got < want:
+			return compare(-1, f.modifier)
+		case got > want:
+			return compare(1, f.modifier)
+		default:
+			return compare(0, f.modifier)
+		}
+	}
+	return false
+}
+
+// compare applies a modifier to the result of comparing a value with a filter value.
+func compare(cmp int, modifier mopinion.FilterModifier) bool {
+	switch modifier {
+	case mopinion.Not:
+		return cmp != 0
+	case mopinion.Lt:
+		return cmp < 0
+	case mopinion.Lte:
+		return cmp <= 0
+	case mopinion.Gt:
+		return cmp > 0
+	case mopinion.Gte:
+		return cmp >= 0
+	default:
+		return cmp == 0
+	}
+}
@@ -0,0 +1,89 @@
+package mopiniontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/oylmz/mopinion"
+)
+
+// Fixtures holds the data a Server is seeded with. The JSON form uses the field names of
+// the mopinion models:
+//
+//	{
+//		"account": {"name": "Acme"},
+//		"reports": [{"id": 1, "name": "Website", "Datasets": [{"id": 2, "name": "Exit survey"}]}],
+//		"fields": [{"DatasetID": 2, "report_id": 1, "key": "nps", "type": "nps"}],
+//		"feedback": [{"id": 1, "dataset_id": 2, "created": "2019-10-01", "fields": [{"key": "nps", "value": 9}]}]
+//	}
+type Fixtures struct {
+	Account     *mopinion.Account       `json:"account"`
+	Reports     []mopinion.Report       `json:"reports"`
+	Datasets    []mopinion.Dataset      `json:"datasets"`
+	Deployments []mopinion.Deployment   `json:"deployments"`
+	Fields      []mopinion.FieldData    `json:"fields"`
+	Feedback    []mopinion.FeedbackData `json:"feedback"`
+}
+
+// LoadFixtures reads fixtures from a JSON file.
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fixtures := new(Fixtures)
+	if err := json.Unmarshal(data, fixtures); err != nil {
+		return nil, fmt.Errorf("parse fixtures %s: %s", path, err)
+	}
+	return fixtures, nil
+}
+
+// Seed adds the fixtures to the data of the server. Reports and datasets without an id
+// get a new one, as does feedback. The report id of fields and feedback is taken from
+// their dataset if it is not set.
+func (s *Server) Seed(fixtures *Fixtures) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if fixtures.Account != nil {
+		s.account = *fixtures.Account
+	}
+	for _, report := range fixtures.Reports {
+		s.putReport(report)
+	}
+	for _, dataset := range fixtures.Datasets {
+		s.putDataset(dataset)
+	}
+	for _, deployment := range fixtures.Deployments {
+		s.deployments[deployment.Key] = deployment
+	}
+	for _, field := range fixtures.Fields {
+		if dataset, ok := s.datasets[field.DatasetID]; ok && field.ReportID == 0 {
+			field.ReportID = dataset.ReportID
+		}
+		s.fields = append(s.fields, field)
+	}
+
+	lastFeedbackID := 0
+	for _, f := range s.feedback {
+		if f.ID > lastFeedbackID {
+			lastFeedbackID = f.ID
+		}
+	}
+	for _, f := range fixtures.Feedback {
+		if f.ID > lastFeedbackID {
+			lastFeedbackID = f.ID
+		}
+	}
+	for _, f := range fixtures.Feedback {
+		if f.ID == 0 {
+			lastFeedbackID++
+			f.ID = lastFeedbackID
+		}
+		if dataset, ok := s.datasets[f.DatasetID]; ok && f.ReportID == 0 {
+			f.ReportID = dataset.ReportID
+		}
+		s.feedback = append(s.feedback, f)
+	}
+}
@@ -0,0 +1,352 @@
+package mopiniontest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/oylmz/mopinion"
+)
+
+// The handlers below are called with mu held.
+
+func (s *Server) reportIDs() []int {
+	ids := make([]int, 0, len(s.reports))
+	for id := range s.reports {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// report returns a copy of the report with its datasets.
+func (s *Server) report(id int) mopinion.Report {
+	report := *s.reports[id]
+	report.Datasets = nil
+	for _, datasetID := range s.datasetIDs() {
+		if dataset := s.datasets[datasetID]; dataset.ReportID == id {
+			report.Datasets = append(report.Datasets, *dataset)
+		}
+	}
+	return report
+}
+
+func (s *Server) datasetIDs() []int {
+	ids := make([]int, 0, len(s.datasets))
+	for id := range s.datasets {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func today() *mopinion.DateTime {
+	return &mopinion.DateTime{Raw: time.Now().Format("2006-01-02")}
+}
+
+func (s *Server) routeReports(r *http.Request, segments []string, body []byte) (int, interface{}, *apiError) {
+	if len(segments) == 0 {
+		if r.Method == "POST" {
+			return s.addReport(body)
+		}
+		return 0, nil, newError(http.StatusNotFound, mopinion.ErrorCodeRouteNotFound)
+	}
+
+	id, err := parseID(segments, mopinion.ErrorCodeReportIDNotSet)
+	if err != nil {
+		return 0, nil, err
+	}
+	if _, ok := s.reports[id]; !ok {
+		return 0, nil, newError(http.StatusNotFound, mopinion.ErrorCodeReportNotFound)
+	}
+
+	switch {
+	case len(segments) == 1 && r.Method == "GET":
+		return http.StatusOK, s.report(id), nil
+	case len(segments) == 1 && r.Method == "PUT":
+		return s.updateReport(id, body)
+	case len(segments) == 1 && r.Method == "DELETE":
+		return s.deleteReport(r, id)
+	case len(segments) == 2 && r.Method == "GET" && segments[1] == "fields":
+		return s.getFields(func(field mopinion.FieldData) bool { return field.ReportID == id })
+	case len(segments) == 2 && r.Method == "GET" && segments[1] == "feedback":
+		return s.getFeedback(r, func(feedback mopinion.FeedbackData) bool { return feedback.ReportID == id })
+	}
+	return 0, nil, newError(http.StatusNotFound, mopinion.ErrorCodeRouteNotFound)
+}
+
+func (s *Server) addReport(body []byte) (int, interface{}, *apiError) {
+	var report mopinion.Report
+	if err := decodeBody(body, &report); err != nil {
+		return 0, nil, err
+	}
+	if report.Name == "" {
+		return 0, nil, newError(http.StatusBadRequest, mopinion.ErrorCodeInvalidRequest)
+	}
+	if s.MaxReports > 0 && len(s.reports) >= s.MaxReports {
+		return 0, nil, newError(http.StatusForbidden, mopinion.ErrorCodeMaxReportsReached)
+	}
+	id := s.putReport(report)
+	return http.StatusCreated, s.report(id), nil
+}
+
+// putReport stores a new report, giving it an id and a creation date.
+func (s *Server) putReport(report mopinion.Report) int {
+	if report.ID == 0 {
+		report.ID = s.nextID()
+	} else if report.ID > s.lastID {
+		s.lastID = report.ID
+	}
+	if report.Created == nil {
+		report.Created = today()
+	}
+	report.Meta = nil
+	datasets := report.Datasets
+	report.Datasets = nil
+	s.reports[report.ID] = &report
+	for _, dataset := range datasets {
+		dataset.ReportID = report.ID
+		s.putDataset(dataset)
+	}
+	return report.ID
+}
+
+func (s *Server) updateReport(id int, body []byte) (int, interface{}, *apiError) {
+	var update mopinion.Report
+	if err := decodeBody(body, &update); err != nil {
+		return 0, nil, err
+	}
+	report := s.reports[id]
+	if update.Name != "" {
+		report.Name = update.Name
+	}
+	if update.Description != "" {
+		report.Description = update.Description
+	}
+	if update.Language != "" {
+		report.Language = update.Language
+	}
+	return http.StatusOK, s.report(id), nil
+}
+
+func (s *Server) deleteReport(r *http.Request, id int) (int, interface{}, *apiError) {
+	affected := map[string]interface{}{"reports": 1, "datasets": 0, "fields": 0, "feedback": 0}
+	var datasetIDs []int
+	for _, datasetID := range s.datasetIDs() {
+		if s.datasets[datasetID].ReportID == id {
+			datasetIDs = append(datasetIDs, datasetID)
+		}
+	}
+	affected["datasets"] = len(datasetIDs)
+	affected["fields"], affected["feedback"] = s.countData(func(reportID, datasetID int) bool { return reportID == id })
+
+	execute := r.URL.Query().Get("dry-run") != "true"
+	if execute {
+		delete(s.reports, id)
+		for _, datasetID := range datasetIDs {
+			delete(s.datasets, datasetID)
+		}
+		s.deleteData(func(reportID, datasetID int) bool { return reportID == id })
+	}
+	return http.StatusOK, mopinion.DeleteResponse{Executed: execute, ResourcesAffected: affected}, nil
+}
+
+func (s *Server) routeDatasets(r *http.Request, segments []string, body []byte) (int, interface{}, *apiError) {
+	if len(segments) == 0 {
+		if r.Method == "POST" {
+			return s.addDataset(body)
+		}
+		return 0, nil, newError(http.StatusNotFound, mopinion.ErrorCodeRouteNotFound)
+	}
+
+	id, err := parseID(segments, mopinion.ErrorCodeDatasetIDNotSet)
+	if err != nil {
+		return 0, nil, err
+	}
+	if _, ok := s.datasets[id]; !ok {
+		return 0, nil, newError(http.StatusNotFound, mopinion.ErrorCodeDatasetNotFound)
+	}
+
+	switch {
+	case len(segments) == 1 && r.Method == "GET":
+		return http.StatusOK, s.datasets[id], nil
+	case len(segments) == 1 && r.Method == "PUT":
+		return s.updateDataset(id, body)
+	case len(segments) == 1 && r.Method == "DELETE":
+		return s.deleteDataset(r, id)
+	case len(segments) == 2 && r.Method == "GET" && segments[1] == "fields":
+		return s.getFields(func(field mopinion.FieldData) bool { return field.DatasetID == id })
+	case len(segments) == 2 && r.Method == "GET" && segments[1] == "feedback":
+		return s.getFeedback(r, func(feedback mopinion.FeedbackData) bool { return feedback.DatasetID == id })
+	}
+	return 0, nil, newError(http.StatusNotFound, mopinion.ErrorCodeRouteNotFound)
+}
+
+func (s *Server) addDataset(body []byte) (int, interface{}, *apiError) {
+	var dataset mopinion.Dataset
+	if err := decodeBody(body, &dataset); err != nil {
+		return 0, nil, err
+	}
+	if dataset.ReportID <= 0 {
+		return 0, nil, newError(http.StatusBadRequest, mopinion.ErrorCodeReportIDNotSet)
+	}
+	if _, ok := s.reports[dataset.ReportID]; !ok {
+		return 0, nil, newError(http.StatusNotFound, mopinion.ErrorCodeReportNotFound)
+	}
+	if dataset.Name == "" {
+		return 0, nil, newError(http.StatusBadRequest, mopinion.ErrorCodeInvalidRequest)
+	}
+	if s.MaxDatasets > 0 && len(s.datasets) >= s.MaxDatasets {
+		return 0, nil, newError(http.StatusForbidden, mopinion.ErrorCodeMaxDatasetsReached)
+	}
+	id := s.putDataset(dataset)
+	return http.StatusCreated, s.datasets[id], nil
+}
+
+// putDataset stores a new dataset, giving it an id.
+func (s *Server) putDataset(dataset mopinion.Dataset) int {
+	if dataset.ID == 0 {
+		dataset.ID = s.nextID()
+	} else if dataset.ID > s.lastID {
+		s.lastID = dataset.ID
+	}
+	dataset.Meta = nil
+	s.datasets[dataset.ID] = &dataset
+	return dataset.ID
+}
+
+func (s *Server) updateDataset(id int, body []byte) (int, interface{}, *apiError) {
+	var update mopinion.Dataset
+	if err := decodeBody(body, &update); err != nil {
+		return 0, nil, err
+	}
+	dataset := s.datasets[id]
+	if update.ReportID != 0 && update.ReportID != dataset.ReportID {
+		if _, ok := s.reports[update.ReportID]; !ok {
+			return 0, nil, newError(http.StatusNotFound, mopinion.ErrorCodeReportNotFound)
+		}
+		dataset.ReportID = update.ReportID
+	}
+	if update.Name != "" {
+		dataset.Name = update.Name
+	}
+	if update.Description != "" {
+		dataset.Description = update.Description
+	}
+	if update.DataSource != "" {
+		dataset.DataSource = update.DataSource
+	}
+	return http.StatusOK, dataset, nil
+}
+
+func (s *Server) deleteDataset(r *http.Request, id int) (int, interface{}, *apiError) {
+	affected := map[string]interface{}{"datasets": 1}
+	affected["fields"], affected["feedback"] = s.countData(func(reportID, datasetID int) bool { return datasetID == id })
+
+	execute := r.URL.Query().Get("dry-run") != "true"
+	if execute {
+		delete(s.datasets, id)
+		s.deleteData(func(reportID, datasetID int) bool { return datasetID == id })
+	}
+	return http.StatusOK, mopinion.DeleteResponse{Executed: execute, ResourcesAffected: affected}, nil
+}
+
+// countData counts the fields and feedback of the matching report and dataset ids.
+func (s *Server) countData(matches func(reportID, datasetID int) bool) (fields, feedback int) {
+	for _, field := range s.fields {
+		if matches(field.ReportID, field.DatasetID) {
+			fields++
+		}
+	}
+	for _, f := range s.feedback {
+		if matches(f.ReportID, f.DatasetID) {
+			feedback++
+		}
+	}
+	return fields, feedback
+}
+
+// deleteData deletes the fields and feedback of the matching report and dataset ids.
+func (s *Server) deleteData(matches func(reportID, datasetID int) bool) {
+	fields := s.fields[:0]
+	for _, field := range s.fields {
+		if !matches(field.ReportID, field.DatasetID) {
+			fields = append(fields, field)
+		}
+	}
+	s.fields = fields
+	feedback := s.feedback[:0]
+	for _, f := range s.feedback {
+		if !matches(f.ReportID, f.DatasetID) {
+			feedback = append(feedback, f)
+		}
+	}
+	s.feedback = feedback
+}
+
+func (s *Server) routeDeployments(r *http.Request, segments []string, body []byte) (int, interface{}, *apiError) {
+	switch {
+	case len(segments) == 0 && r.Method == "GET":
+		keys := make([]string, 0, len(s.deployments))
+		for key := range s.deployments {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		deployments := make([]mopinion.Deployment, len(keys))
+		for i, key := range keys {
+			deployments[i] = s.deployments[key]
+		}
+		return http.StatusOK, deploymentsResponse(deployments), nil
+	case len(segments) == 0 && r.Method == "POST":
+		return s.addDeployment(body)
+	case len(segments) <= 1 && r.Method == "DELETE":
+		key := ""
+		if len(segments) == 1 {
+			key = segments[0]
+		}
+		return s.deleteDeployment(r, key)
+	}
+	return 0, nil, newError(http.StatusNotFound, mopinion.ErrorCodeRouteNotFound)
+}
+
+// deploymentsResponse lists deployments the way the API does, as an object keyed by index.
+func deploymentsResponse(deployments []mopinion.Deployment) map[string]interface{} {
+	response := map[string]interface{}{"_meta": meta(len(deployments), len(deployments), false, "", "")}
+	for i, deployment := range deployments {
+		response[strconv.Itoa(i)] = map[string]string{"key": deployment.Key, "name": deployment.Name}
+	}
+	return response
+}
+
+func (s *Server) addDeployment(body []byte) (int, interface{}, *apiError) {
+	var deployment mopinion.Deployment
+	if err := decodeBody(body, &deployment); err != nil {
+		return 0, nil, err
+	}
+	if deployment.Key == "" {
+		return 0, nil, newError(http.StatusBadRequest, mopinion.ErrorCodeNoDeploymentCode)
+	}
+	if deployment.Name == "" {
+		return 0, nil, newError(http.StatusBadRequest, mopinion.ErrorCodeInvalidRequest)
+	}
+	if _, ok := s.deployments[deployment.Key]; ok {
+		return 0, nil, newError(http.StatusConflict, mopinion.ErrorCodeFailedToCreateResource)
+	}
+	s.deployments[deployment.Key] = deployment
+	return http.StatusCreated, deploymentsResponse([]mopinion.Deployment{deployment}), nil
+}
+
+func (s *Server) deleteDeployment(r *http.Request, key string) (int, interface{}, *apiError) {
+	if key == "" {
+		return 0, nil, newError(http.StatusBadRequest, mopinion.ErrorCodeDeploymentIDNotSet)
+	}
+	if _, ok := s.deployments[key]; !ok {
+		return 0, nil, newError(http.StatusNotFound, mopinion.ErrorCodeDeploymentNotFound)
+	}
+	execute := r.URL.Query().Get("dry-run") != "true"
+	if execute {
+		delete(s.deployments, key)
+	}
+	return http.StatusOK, mopinion.DeleteResponse{Executed: execute, ResourcesAffected: map[string]interface{}{"deployments": 1}}, nil
+}
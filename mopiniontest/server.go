@@ -0,0 +1,281 @@
+// Package mopiniontest provides an in-memory fake of the Mopinion API for tests.
+//
+// The fake keeps state between requests: reports, datasets and deployments can be
+// created, updated and deleted, and fields and feedback can be seeded from fixtures.
+// Requests are authenticated like the real API, so a mopinion.Client has to fetch a
+// token with the right keys and sign every request with it.
+//
+//	server := mopiniontest.NewServer("publickey", "privatekey")
+//	defer server.Close()
+//	server.Seed(&mopiniontest.Fixtures{Reports: []mopinion.Report{{Name: "Website"}}})
+//
+//	client, err := server.NewClient()
+//	...
+//	report, _, err := client.Reports.Get(ctx, 1)
+package mopiniontest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/oylmz/mopinion"
+)
+
+// DefaultLimit is the number of feedback items per page when no limit is requested.
+const DefaultLimit = 10
+
+// Server is a fake Mopinion API listening on a local address. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// MaxReports and MaxDatasets limit how many reports and datasets the account may have,
+	// if positive. They should be set before any request is made.
+	MaxReports  int
+	MaxDatasets int
+
+	// publicKey and privateKey are the keys the server was started with.
+	publicKey  string
+	privateKey string
+
+	mu          sync.Mutex
+	credentials map[string]string // public key to private key
+	tokens      map[string]string // token to public key
+	account     mopinion.Account
+	reports     map[int]*mopinion.Report
+	datasets    map[int]*mopinion.Dataset
+	deployments map[string]mopinion.Deployment
+	fields      []mopinion.FieldData
+	feedback    []mopinion.FeedbackData
+	lastID      int
+}
+
+// NewServer starts a fake API accepting the given keys. The caller should call Close
+// when finished, to shut it down.
+func NewServer(publicKey, privateKey string) *Server {
+	s := &Server{
+		publicKey:   publicKey,
+		privateKey:  privateKey,
+		credentials: map[string]string{publicKey: privateKey},
+		tokens:      map[string]string{},
+		account:     mopinion.Account{Name: "Mopinion Test", Package: "test"},
+		reports:     map[int]*mopinion.Report{},
+		datasets:    map[int]*mopinion.Dataset{},
+		deployments: map[string]mopinion.Deployment{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// BaseURL returns the base URL of the fake API, to be used with mopinion.WithBaseURL.
+func (s *Server) BaseURL() string {
+	return s.URL + "/"
+}
+
+// NewClient returns a client talking to the fake API with the keys the server was started
+// with, even after other keys are added. The options are applied after the base URL is set.
+func (s *Server) NewClient(options ...mopinion.Option) (*mopinion.Client, error) {
+	options = append([]mopinion.Option{mopinion.WithBaseURL(s.BaseURL())}, options...)
+	return mopinion.NewClient(mopinion.NewBasicCredentialProvider(s.publicKey, s.privateKey), options...)
+}
+
+// AddCredentials makes the server accept another pair of keys, as when keys are rotated.
+func (s *Server) AddCredentials(publicKey, privateKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.credentials[publicKey] = privateKey
+}
+
+// RemoveCredentials makes the server reject the keys and the tokens issued for them.
+func (s *Server) RemoveCredentials(publicKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.credentials, publicKey)
+	for token, key := range s.tokens {
+		if key == publicKey {
+			delete(s.tokens, token)
+		}
+	}
+}
+
+// RevokeTokens rejects all tokens issued so far, as if they expired.
+func (s *Server) RevokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]string{}
+}
+
+// apiError is an error response of the API.
+type apiError struct {
+	status int
+	code   mopinion.ErrorCode
+}
+
+func newError(status int, code mopinion.ErrorCode) *apiError {
+	return &apiError{status: status, code: code}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	status, v, err := s.route(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, status, v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err *apiError) {
+	writeJSON(w, err.status, map[string]interface{}{
+		"status":     err.status,
+		"error_code": int(err.code),
+		"title":      err.code.Title(),
+		"type":       fmt.Sprintf("https://developer.mopinion.com/api/error-codes#%d", int(err.code)),
+	})
+}
+
+// route authenticates the request and hands it to the handler of its path.
+func (s *Server) route(r *http.Request) (int, interface{}, *apiError) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return 0, nil, newError(http.StatusBadRequest, mopinion.ErrorCodeInvalidRequest)
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/token":
+		return s.issueToken(r)
+	case r.Method == "GET" && r.URL.Path == "/ping":
+		return http.StatusOK, map[string]interface{}{"code": 200, "message": "OK"}, nil
+	}
+	if err := s.authenticate(r); err != nil {
+		return 0, nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch segments[0] {
+	case "account":
+		if len(segments) == 1 && r.Method == "GET" {
+			return s.getAccount()
+		}
+	case "reports":
+		return s.routeReports(r, segments[1:], body)
+	case "datasets":
+		return s.routeDatasets(r, segments[1:], body)
+	case "deployments":
+		return s.routeDeployments(r, segments[1:], body)
+	}
+	return 0, nil, newError(http.StatusNotFound, mopinion.ErrorCodeRouteNotFound)
+}
+
+// issueToken returns a new token if the request carries valid keys with Basic authentication.
+func (s *Server) issueToken(r *http.Request) (int, interface{}, *apiError) {
+	publicKey, privateKey, ok := r.BasicAuth()
+	if !ok {
+		return 0, nil, newError(http.StatusUnauthorized, mopinion.ErrorCodeNotAuthenticated)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expected, ok := s.credentials[publicKey]
+	if !ok {
+		return 0, nil, newError(http.StatusUnauthorized, mopinion.ErrorCodePublicKeyNotFound)
+	}
+	if !hmac.Equal([]byte(expected), []byte(privateKey)) {
+		return 0, nil, newError(http.StatusUnauthorized, mopinion.ErrorCodeNotAuthenticated)
+	}
+
+	random := make([]byte, 16)
+	rand.Read(random)
+	token := hex.EncodeToString(random)
+	s.tokens[token] = publicKey
+	return http.StatusOK, map[string]string{"token": token}, nil
+}
+
+// authenticate checks the x-auth-token header against the tokens issued for its public key.
+func (s *Server) authenticate(r *http.Request) *apiError {
+	verifier := mopinion.NewVerifier(func(publicKey string) ([]string, bool) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.credentials[publicKey]; !ok {
+			return nil, false
+		}
+		var tokens []string
+		for token, key := range s.tokens {
+			if key == publicKey {
+				tokens = append(tokens, token)
+			}
+		}
+		return tokens, true
+	})
+	if _, err := verifier.Verify(r); err != nil {
+		if errors.Is(err, mopinion.ErrUnknownPublicKey) {
+			return newError(http.StatusUnauthorized, mopinion.ErrorCodePublicKeyNotFound)
+		}
+		return newError(http.StatusUnauthorized, mopinion.ErrorCodeInvalidToken)
+	}
+	return nil
+}
+
+func (s *Server) getAccount() (int, interface{}, *apiError) {
+	account := s.account
+	account.Reports = nil
+	for _, id := range s.reportIDs() {
+		account.Reports = append(account.Reports, s.report(id))
+	}
+	account.NumberReports = len(account.Reports)
+	account.Meta = meta(1, 1, false, false, "")
+	return http.StatusOK, account, nil
+}
+
+func meta(count, total int, hasMore bool, previous, next interface{}) mopinion.Meta {
+	if previous == "" {
+		previous = false
+	}
+	if next == "" {
+		next = false
+	}
+	return mopinion.Meta{Code: 200, Message: "OK", Count: count, Total: total, HasMore: hasMore, Previous: previous, Next: next}
+}
+
+// nextID returns a new id for a report or dataset. The caller must hold mu.
+func (s *Server) nextID() int {
+	s.lastID++
+	return s.lastID
+}
+
+// parseID parses the id in a path, returning notSet if it is missing or not positive.
+func parseID(segments []string, notSet mopinion.ErrorCode) (int, *apiError) {
+	if len(segments) == 0 || segments[0] == "" {
+		return 0, newError(http.StatusBadRequest, notSet)
+	}
+	id, err := strconv.Atoi(segments[0])
+	if err != nil || id <= 0 {
+		return 0, newError(http.StatusBadRequest, notSet)
+	}
+	return id, nil
+}
+
+func decodeBody(body []byte, v interface{}) *apiError {
+	if err := json.Unmarshal(body, v); err != nil {
+		return newError(http.StatusBadRequest, mopinion.ErrorCodeInvalidJSON)
+	}
+	return nil
+}
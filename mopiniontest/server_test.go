@@ -0,0 +1,283 @@
+package mopiniontest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/oylmz/mopinion"
+)
+
+func setup(t *testing.T) (*Server, *mopinion.Client) {
+	server := NewServer("publickey", "privatekey")
+	fixtures, err := LoadFixtures("testdata/fixtures.json")
+	if err != nil {
+		server.Close()
+		t.Fatalf("loading fixtures should not return an error: %s", err)
+	}
+	server.Seed(fixtures)
+
+	client, err := server.NewClient()
+	if err != nil {
+		server.Close()
+		t.Fatalf("creating a client should not return an error: %s", err)
+	}
+	return server, client
+}
+
+func feedbackIDs(feedback []mopinion.FeedbackData) []int {
+	ids := make([]int, len(feedback))
+	for i, f := range feedback {
+		ids[i] = f.ID
+	}
+	return ids
+}
+
+func TestAuthentication(t *testing.T) {
+	server, client := setup(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	account, _, err := client.Account.Get(ctx)
+	if err != nil {
+		t.Fatalf("account API should not return an error: %s", err)
+	}
+	if account.Name != "Acme" || len(account.Reports) != 1 || len(account.Reports[0].Datasets) != 1 {
+		t.Errorf("expected the seeded account but got: %+v", account)
+	}
+
+	// The client gets a new token when the old one is rejected.
+	server.RevokeTokens()
+	if _, _, err := client.Account.Get(ctx); err != nil {
+		t.Errorf("account API should not return an error after the tokens are revoked: %s", err)
+	}
+
+	wrongKey, err := mopinion.NewClient(mopinion.NewBasicCredentialProvider("publickey", "wrong"), mopinion.WithBaseURL(server.BaseURL()))
+	if err != nil {
+		t.Fatalf("creating a client should not return an error: %s", err)
+	}
+	if _, _, err := wrongKey.Account.Get(ctx); !errors.Is(err, mopinion.ErrNotAuthenticated) {
+		t.Errorf("expected ErrNotAuthenticated but got: %v", err)
+	}
+
+	unknownKey, err := mopinion.NewClient(mopinion.NewBasicCredentialProvider("otherkey", "privatekey"), mopinion.WithBaseURL(server.BaseURL()))
+	if err != nil {
+		t.Fatalf("creating a client should not return an error: %s", err)
+	}
+	if _, _, err := unknownKey.Account.Get(ctx); !errors.Is(err, mopinion.ErrPublicKeyNotFound) {
+		t.Errorf("expected ErrPublicKeyNotFound but got: %v", err)
+	}
+}
+
+func TestNewClientKeys(t *testing.T) {
+	server, _ := setup(t)
+	defer server.Close()
+	server.AddCredentials("otherkey", "otherprivatekey")
+
+	// Every client signs with the original keys, so removing the others does not affect them.
+	for i := 0; i < 20; i++ {
+		client, err := server.NewClient()
+		if err != nil {
+			t.Fatalf("creating a client should not return an error: %s", err)
+		}
+		if _, _, err := client.Token.Get(context.Background()); err != nil {
+			t.Fatalf("token API should not return an error: %s", err)
+		}
+		server.RemoveCredentials("otherkey")
+		if _, _, err := client.Account.Get(context.Background()); err != nil {
+			t.Errorf("client %d should use the original keys, but got: %v", i, err)
+		}
+		server.AddCredentials("otherkey", "otherprivatekey")
+	}
+}
+
+func TestForgedToken(t *testing.T) {
+	server, client := setup(t)
+	defer server.Close()
+
+	client.SetToken(&mopinion.Token{Token: "forged"})
+	req, err := client.NewRequest("GET", "account", nil)
+	if err != nil {
+		t.Fatalf("creating a request should not return an error: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("sending the request should not return an error: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status: %d but got: %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+}
+
+func TestReports(t *testing.T) {
+	server, client := setup(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	report, _, err := client.Reports.Add(ctx, &mopinion.Report{Name: "App", Language: "nl_NL"})
+	if err != nil {
+		t.Fatalf("adding a report should not return an error: %s", err)
+	}
+	if report.ID == 0 || report.Created == nil {
+		t.Errorf("expected an id and creation date but got: %+v", report)
+	}
+
+	report.Description = "Feedback from the app"
+	if _, _, err := client.Reports.Update(ctx, report); err != nil {
+		t.Fatalf("updating a report should not return an error: %s", err)
+	}
+	got, _, err := client.Reports.Get(ctx, report.ID)
+	if err != nil || got.Description != "Feedback from the app" {
+		t.Errorf("expected the updated report but got: %+v, %v", got, err)
+	}
+
+	deleted, _, err := client.Reports.Delete(ctx, 1, true)
+	if err != nil || deleted.Executed || deleted.ResourcesAffected["feedback"] != float64(5) {
+		t.Errorf("expected a dry run deleting 5 feedback items but got: %+v, %v", deleted, err)
+	}
+	if _, _, err := client.Reports.Delete(ctx, 1, false); err != nil {
+		t.Fatalf("deleting a report should not return an error: %s", err)
+	}
+	if _, _, err := client.Reports.Get(ctx, 1); !errors.Is(err, mopinion.ErrReportNotFound) {
+		t.Errorf("expected ErrReportNotFound but got: %v", err)
+	}
+	if _, _, err := client.Datasets.Get(ctx, 2); !errors.Is(err, mopinion.ErrDatasetNotFound) {
+		t.Errorf("datasets should be deleted along with their report, but got: %v", err)
+	}
+	if _, _, err := client.Reports.Update(ctx, &mopinion.Report{Name: "no id"}); !errors.Is(err, mopinion.ErrReportIDNotSet) {
+		t.Errorf("expected ErrReportIDNotSet but got: %v", err)
+	}
+
+	server.MaxReports = 1
+	if _, _, err := client.Reports.Add(ctx, &mopinion.Report{Name: "one too many"}); !errors.Is(err, mopinion.ErrMaxReportsReached) {
+		t.Errorf("expected ErrMaxReportsReached but got: %v", err)
+	}
+}
+
+func TestDatasets(t *testing.T) {
+	server, client := setup(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	dataset, _, err := client.Datasets.Add(ctx, &mopinion.Dataset{ReportID: 1, Name: "Campaign"})
+	if err != nil {
+		t.Fatalf("adding a dataset should not return an error: %s", err)
+	}
+	if got, _, err := client.Datasets.Get(ctx, dataset.ID); err != nil || got.Name != "Campaign" {
+		t.Errorf("expected the new dataset but got: %+v, %v", got, err)
+	}
+	if _, _, err := client.Datasets.Add(ctx, &mopinion.Dataset{ReportID: 99, Name: "Orphan"}); !errors.Is(err, mopinion.ErrReportNotFound) {
+		t.Errorf("expected ErrReportNotFound but got: %v", err)
+	}
+	if _, _, err := client.Datasets.Update(ctx, &mopinion.Dataset{ID: 99, Name: "Missing"}); !errors.Is(err, mopinion.ErrDatasetNotFound) {
+		t.Errorf("expected ErrDatasetNotFound but got: %v", err)
+	}
+
+	server.MaxDatasets = 2
+	if _, _, err := client.Datasets.Add(ctx, &mopinion.Dataset{ReportID: 1, Name: "One too many"}); !errors.Is(err, mopinion.ErrMaxDatasetsReached) {
+		t.Errorf("expected ErrMaxDatasetsReached but got: %v", err)
+	}
+}
+
+func TestDeployments(t *testing.T) {
+	server, client := setup(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	if _, _, err := client.Deployments.Add(ctx, &mopinion.Deployment{Key: "dpg93g038fm", Name: "Staging"}); err != nil {
+		t.Fatalf("adding a deployment should not return an error: %s", err)
+	}
+	deployments, _, err := client.Deployments.Get(ctx)
+	if err != nil {
+		t.Fatalf("deployments API should not return an error: %s", err)
+	}
+	expected := []mopinion.Deployment{{Key: "ab25of859d3", Name: "Default implementation"}, {Key: "dpg93g038fm", Name: "Staging"}}
+	if !reflect.DeepEqual(expected, deployments.Deployments) {
+		t.Errorf("expected deployments: %+v but got: %+v", expected, deployments.Deployments)
+	}
+
+	if _, _, err := client.Deployments.Add(ctx, &mopinion.Deployment{Name: "No key"}); !errors.Is(err, mopinion.ErrNoDeploymentCode) {
+		t.Errorf("expected ErrNoDeploymentCode but got: %v", err)
+	}
+	if _, _, err := client.Deployments.Delete(ctx, "unknown", false); !errors.Is(err, mopinion.ErrDeploymentNotFound) {
+		t.Errorf("expected ErrDeploymentNotFound but got: %v", err)
+	}
+}
+
+func TestFields(t *testing.T) {
+	server, client := setup(t)
+	defer server.Close()
+
+	fields, _, err := client.Fields.GetByReport(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("fields API should not return an error: %s", err)
+	}
+	if len(fields.Data) != 3 || fields.Meta.Total != 3 {
+		t.Errorf("expected 3 fields but got: %+v", fields)
+	}
+	if _, _, err := client.Fields.GetByDataset(context.Background(), 99); !errors.Is(err, mopinion.ErrDatasetNotFound) {
+		t.Errorf("expected ErrDatasetNotFound but got: %v", err)
+	}
+}
+
+func TestFeedbackPagination(t *testing.T) {
+	server, client := setup(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	first, _, err := client.Feedback.GetByDataset(ctx, 2, &mopinion.PaginationOptions{Limit: 2, Order: "desc"}, nil)
+	if err != nil {
+		t.Fatalf("feedback API should not return an error: %s", err)
+	}
+	if ids := feedbackIDs(first.Data); !reflect.DeepEqual([]int{5, 4}, ids) || !first.Meta.HasMore || first.Meta.Total != 5 {
+		t.Errorf("expected the first page in descending order but got: %v, %+v", ids, first.Meta)
+	}
+	second, _, err := client.Feedback.FollowNext(ctx, &first.Meta)
+	if err != nil {
+		t.Fatalf("following the next link should not return an error: %s", err)
+	}
+	if ids := feedbackIDs(second.Data); !reflect.DeepEqual([]int{3, 2}, ids) {
+		t.Errorf("expected the second page but got: %v", ids)
+	}
+
+	all, err := client.Feedback.BulkByReport(ctx, 1, &mopinion.BulkOptions{Limit: 2}, nil)
+	if ids := feedbackIDs(all); err != nil || !reflect.DeepEqual([]int{1, 2, 3, 4, 5}, ids) {
+		t.Errorf("expected all feedback but got: %v, %v", ids, err)
+	}
+
+	if _, _, err := client.Feedback.GetByDataset(ctx, 2, &mopinion.PaginationOptions{Page: 4, Limit: 2}, nil); !errors.Is(err, mopinion.ErrPageOutOfRange) {
+		t.Errorf("expected ErrPageOutOfRange but got: %v", err)
+	}
+}
+
+func TestFeedbackFilters(t *testing.T) {
+	server, client := setup(t)
+	defer server.Close()
+
+	tests := map[string][]int{
+		"nps >= 9":                  {1, 2, 4, 5},
+		"nps >= 9 and tags != spam": {1, 4, 5},
+		"date >= 2019-10-01 and date < 2019-10-04": {2, 3, 4},
+		"ces <= 2":         {1, 2},
+		"ces_inverse >= 4": {1, 2},
+		"gcr = yes":        {1, 2, 5},
+		"tags = vip":       {4},
+	}
+	for expression, expected := range tests {
+		filters, err := mopinion.ParseFilters(expression)
+		if err != nil {
+			t.Fatalf("parsing %q should not return an error: %s", expression, err)
+		}
+		feedback, _, err := client.Feedback.GetByReport(context.Background(), 1, &mopinion.PaginationOptions{Limit: 10}, filters)
+		if err != nil {
+			t.Errorf("%s: feedback API should not return an error: %s", expression, err)
+			continue
+		}
+		if ids := feedbackIDs(feedback.Data); !reflect.DeepEqual(expected, ids) {
+			t.Errorf("%s: expected feedback ids: %v but got: %v", expression, expected, ids)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package mopinion
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option configures a Client. Options are passed to NewClient
+// and are validated there, so a misconfigured Client is never returned.
+type Option func(c *Client) error
+
+// WithBaseURL sets the url of the Mopinion API. It must be absolute and end with a slash.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("parse base url: %s", err)
+		}
+		if !u.IsAbs() || u.Host == "" {
+			return fmt.Errorf("BaseURL must be an absolute url, but %q is not", baseURL)
+		}
+		if !strings.HasSuffix(u.Path, "/") {
+			return fmt.Errorf("BaseURL must have a trailing slash, but %q does not", baseURL)
+		}
+		c.BaseURL = u
+		return nil
+	}
+}
+
+// WithHTTPClient sets the http client used to communicate with the Mopinion API.
+// http.DefaultClient is used by default.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return fmt.Errorf("http client cannot be nil")
+		}
+		c.client = httpClient
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent along with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		if userAgent == "" {
+			return fmt.Errorf("user agent cannot be empty")
+		}
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithTimeout sets a time limit for every request made by the client.
+// The http client given with WithHTTPClient is copied rather than modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout <= 0 {
+			return fmt.Errorf("timeout must be positive, but got %s", timeout)
+		}
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the policy deciding whether failed requests are sent again.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) error {
+		if policy == nil {
+			return fmt.Errorf("retry policy cannot be nil")
+		}
+		c.RetryPolicy = policy
+		return nil
+	}
+}
+
+// WithDateLocation sets the location of the dates and times sent by the API without a time
+// zone, such as "2019-05-02". They are parsed in UTC by default.
+func WithDateLocation(location *time.Location) Option {
+	return func(c *Client) error {
+		if location == nil {
+			return fmt.Errorf("date location cannot be nil")
+		}
+		c.dateLocation = location
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+package mopinion
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	httpClient := &http.Client{}
+	policy := NewBackoffRetryPolicy()
+	client, err := NewClient(NewBasicCredentialProvider("publickey", "privatekey"),
+		WithBaseURL("https://example.com/api/"),
+		WithHTTPClient(httpClient),
+		WithUserAgent("agent"),
+		WithTimeout(time.Second),
+		WithRetryPolicy(policy),
+	)
+	if err != nil {
+		t.Fatalf("NewClient should not return an error: %s", err)
+	}
+
+	if client.BaseURL.String() != "https://example.com/api/" {
+		t.Errorf("expected base url: %v but got: %v", "https://example.com/api/", client.BaseURL)
+	}
+	if client.UserAgent != "agent" {
+		t.Errorf("expected user agent: %v but got: %v", "agent", client.UserAgent)
+	}
+	if client.RetryPolicy != policy {
+		t.Errorf("expected retry policy: %v but got: %v", policy, client.RetryPolicy)
+	}
+	if client.client.Timeout != time.Second {
+		t.Errorf("expected timeout: %v but got: %v", time.Second, client.client.Timeout)
+	}
+	if httpClient.Timeout != 0 {
+		t.Errorf("given http client should not be modified")
+	}
+}
+
+func TestNewClientDefaults(t *testing.T) {
+	client, err := NewClient(NewBasicCredentialProvider("publickey", "privatekey"))
+	if err != nil {
+		t.Fatalf("NewClient should not return an error: %s", err)
+	}
+	if client.BaseURL.String() != defaultBaseURL {
+		t.Errorf("expected base url: %v but got: %v", defaultBaseURL, client.BaseURL)
+	}
+	if client.client != http.DefaultClient {
+		t.Errorf("expected http.DefaultClient to be used")
+	}
+}
+
+func TestNewClientInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		option Option
+	}{
+		{"base url without trailing slash", WithBaseURL("https://example.com/api")},
+		{"relative base url", WithBaseURL("api/")},
+		{"nil http client", WithHTTPClient(nil)},
+		{"empty user agent", WithUserAgent("")},
+		{"negative timeout", WithTimeout(-time.Second)},
+		{"nil retry policy", WithRetryPolicy(nil)},
+	}
+	for _, test := range tests {
+		client, err := NewClient(NewBasicCredentialProvider("publickey", "privatekey"), test.option)
+		if err == nil {
+			t.Errorf("%s: NewClient should return an error", test.name)
+		}
+		if client != nil {
+			t.Errorf("%s: client should be nil but got: %v", test.name, client)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package mopinion
+
+import "context"
+
+// PingInterface holds only one method for checking whether the Mopinion API is reachable.
+type PingInterface interface {
+	Get(ctx context.Context) (*Response, error)
+}
+
+// PingService implements PingInterface.
+type PingService struct {
+	service
+}
+
+// Get pings the Mopinion API. It does not require a token.
+func (s *PingService) Get(ctx context.Context) (*Response, error) {
+	req, err := s.client.NewRequest("GET", "ping", nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(ctx, req, nil)
+}
@@ -0,0 +1,31 @@
+package mopinion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestPing(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-auth-token") != "" {
+			t.Errorf("ping request should not be signed")
+		}
+		fmt.Fprint(w, `{"code": 200, "message": "OK"}`)
+	})
+
+	response, err := client.Ping.Get(context.Background())
+	if err != nil {
+		t.Errorf("ping API should not return an error: %s", err)
+	}
+	if response == nil || response.StatusCode != http.StatusOK {
+		t.Errorf("expected status code: %v but got: %v", http.StatusOK, response)
+	}
+	if client.getToken() != nil {
+		t.Errorf("ping should not get a token")
+	}
+}
@@ -0,0 +1,178 @@
+package mopinion
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Predicate reports whether a feedback item matches. Predicates filter feedback locally,
+// on fields the API cannot filter on:
+//
+//	checkout := mopinion.And(
+//		mopinion.FieldKey("url").Contains("/checkout"),
+//		mopinion.Or(
+//			mopinion.FieldLabel("How satisfied are you?").Lte(2),
+//			mopinion.FieldKey("category").Regexp(regexp.MustCompile(`(?i)payment`)),
+//		),
+//	)
+type Predicate func(feedback FeedbackData) bool
+
+// And matches when all predicates match.
+func And(predicates ...Predicate) Predicate {
+	return func(feedback FeedbackData) bool {
+		for _, p := range predicates {
+			if !p(feedback) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches when any of the predicates matches.
+func Or(predicates ...Predicate) Predicate {
+	return func(feedback FeedbackData) bool {
+		for _, p := range predicates {
+			if p(feedback) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Negate matches when the predicate does not match.
+func Negate(predicate Predicate) Predicate {
+	return func(feedback FeedbackData) bool {
+		return !predicate(feedback)
+	}
+}
+
+// FieldCondition selects the fields of a feedback item that a predicate is applied to.
+// A predicate built from it matches when any of the selected fields matches. Values that
+// are lists, such as the answers of a multiple choice question, match when any item does.
+type FieldCondition struct {
+	selects func(field FeedbackField) bool
+}
+
+// FieldKey selects the fields with the given key.
+func FieldKey(key string) *FieldCondition {
+	return &FieldCondition{selects: func(field FeedbackField) bool { return field.Key == key }}
+}
+
+// FieldLabel selects the fields with the given label.
+func FieldLabel(label string) *FieldCondition {
+	return &FieldCondition{selects: func(field FeedbackField) bool { return field.Label == label }}
+}
+
+func (c *FieldCondition) match(matches func(value interface{}) bool) Predicate {
+	return func(feedback FeedbackData) bool {
+		for _, field := range feedback.Fields {
+			if !c.selects(field) {
+				continue
+			}
+			if values, ok := field.Value.([]interface{}); ok {
+				for _, value := range values {
+					if matches(value) {
+						return true
+					}
+				}
+				continue
+			}
+			if matches(field.Value) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (c *FieldCondition) matchString(matches func(value string) bool) Predicate {
+	return c.match(func(value interface{}) bool {
+		s, ok := fieldString(value)
+		return ok && matches(s)
+	})
+}
+
+func (c *FieldCondition) matchNumber(matches func(value float64) bool) Predicate {
+	return c.match(func(value interface{}) bool {
+		n, ok := fieldNumber(value)
+		return ok && matches(n)
+	})
+}
+
+// Exists matches when the feedback has the field with a value other than null.
+func (c *FieldCondition) Exists() Predicate {
+	return c.match(func(value interface{}) bool { return value != nil })
+}
+
+// Equals matches when the value equals s.
+func (c *FieldCondition) Equals(s string) Predicate {
+	return c.matchString(func(value string) bool { return value == s })
+}
+
+// Contains matches when the value contains s.
+func (c *FieldCondition) Contains(s string) Predicate {
+	return c.matchString(func(value string) bool { return strings.Contains(value, s) })
+}
+
+// Regexp matches when the value matches re.
+func (c *FieldCondition) Regexp(re *regexp.Regexp) Predicate {
+	return c.matchString(re.MatchString)
+}
+
+// Eq matches when the value is a number equal to n.
+func (c *FieldCondition) Eq(n float64) Predicate {
+	return c.matchNumber(func(value float64) bool { return value == n })
+}
+
+// Lt matches when the value is a number less than n.
+func (c *FieldCondition) Lt(n float64) Predicate {
+	return c.matchNumber(func(value float64) bool { return value < n })
+}
+
+// Lte matches when the value is a number less than or equal to n.
+func (c *FieldCondition) Lte(n float64) Predicate {
+	return c.matchNumber(func(value float64) bool { return value <= n })
+}
+
+// Gt matches when the value is a number greater than n.
+func (c *FieldCondition) Gt(n float64) Predicate {
+	return c.matchNumber(func(value float64) bool { return value > n })
+}
+
+// Gte matches when the value is a number greater than or equal to n.
+func (c *FieldCondition) Gte(n float64) Predicate {
+	return c.matchNumber(func(value float64) bool { return value >= n })
+}
+
+// fieldString returns the text of a decoded JSON value. Objects have no text.
+func fieldString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case nil, map[string]interface{}:
+		return "", false
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+// fieldNumber returns the number of a decoded JSON value; numbers are often sent as text.
+func fieldNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
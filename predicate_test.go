@@ -0,0 +1,93 @@
+package mopinion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestPredicates(t *testing.T) {
+	var feedback FeedbackData
+	err := json.Unmarshal([]byte(`{
+		"id": 1,
+		"fields": [
+			{"key": "url", "label": "Page URL", "value": "https://example.com/checkout/payment"},
+			{"key": "score", "label": "How satisfied are you?", "value": "4"},
+			{"key": "nps", "label": "Would you recommend us?", "value": 8},
+			{"key": "topics", "label": "Topics", "value": ["delivery", "price"]},
+			{"key": "comment", "label": "Comment", "value": null}
+		]
+	}`), &feedback)
+	if err != nil {
+		t.Fatalf("unmarshaling should not return an error: %s", err)
+	}
+
+	tests := []struct {
+		name      string
+		predicate Predicate
+		expected  bool
+	}{
+		{"equals", FieldKey("url").Equals("https://example.com/checkout/payment"), true},
+		{"equals other", FieldKey("url").Equals("https://example.com/"), false},
+		{"contains", FieldKey("url").Contains("/checkout"), true},
+		{"regexp", FieldKey("url").Regexp(regexp.MustCompile(`/checkout/\w+$`)), true},
+		{"label", FieldLabel("How satisfied are you?").Gte(4), true},
+		{"numeric string", FieldKey("score").Lt(4), false},
+		{"number", FieldKey("nps").Eq(8), true},
+		{"number as text", FieldKey("nps").Equals("8"), true},
+		{"number on text", FieldKey("url").Gt(0), false},
+		{"list", FieldKey("topics").Equals("price"), true},
+		{"list other", FieldKey("topics").Equals("service"), false},
+		{"exists", FieldKey("url").Exists(), true},
+		{"null", FieldKey("comment").Exists(), false},
+		{"missing", FieldKey("unknown").Equals(""), false},
+		{"and", And(FieldKey("nps").Gt(7), FieldKey("url").Contains("checkout")), true},
+		{"and false", And(FieldKey("nps").Gt(7), FieldKey("url").Contains("account")), false},
+		{"or", Or(FieldKey("nps").Lt(5), FieldKey("topics").Equals("delivery")), true},
+		{"or false", Or(FieldKey("nps").Lt(5), FieldKey("topics").Equals("service")), false},
+		{"negate", Negate(FieldKey("comment").Exists()), true},
+	}
+
+	for _, test := range tests {
+		if matched := test.predicate(feedback); matched != test.expected {
+			t.Errorf("%s: expected %v but got: %v", test.name, test.expected, matched)
+		}
+	}
+}
+
+func TestFeedbackIteratorWhere(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/1/feedback", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("filter[>>date]") != "2019-10-01" {
+			t.Errorf("filters should be sent to the API, but got query: %s", r.URL.RawQuery)
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `{"data": [
+				{"id": 1, "fields": [{"key": "url", "value": "/home"}]},
+				{"id": 2, "fields": [{"key": "url", "value": "/checkout"}]}
+			], "_meta": {"has_more": true}}`)
+		case "2":
+			fmt.Fprint(w, `{"data": [
+				{"id": 3, "fields": [{"key": "url", "value": "/checkout/payment"}, {"key": "nps", "value": 3}]},
+				{"id": 4, "fields": [{"key": "url", "value": "/checkout/payment"}, {"key": "nps", "value": 9}]}
+			], "_meta": {"has_more": false}}`)
+		}
+	})
+
+	it := client.Feedback.IterateByReport(1, nil, filterCollection).
+		Where(FieldKey("url").Contains("/checkout")).
+		Where(Negate(FieldKey("nps").Lt(5)))
+	ids := collectFeedbackIDs(it)
+	if err := it.Err(); err != nil {
+		t.Errorf("iterator should not return an error: %s", err)
+	}
+	if expected := []int{2, 4}; !reflect.DeepEqual(expected, ids) {
+		t.Errorf("expected feedback ids: %v but got: %v", expected, ids)
+	}
+}
@@ -0,0 +1,143 @@
+package mopinion
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter paces the requests sent by the client. Every request, including retries
+// and token requests, waits on it right before it is sent. The time spent waiting is
+// reported in Response.RateLimitWait and by the logger.
+type RateLimiter interface {
+	// Wait blocks until the request may be sent and returns how long it waited.
+	// It returns an error if ctx is done first.
+	Wait(ctx context.Context, req *http.Request) (time.Duration, error)
+}
+
+// TokenBucket is a RateLimiter allowing a steady rate of requests with bursts. It is safe
+// for concurrent use, so one TokenBucket can be shared by several clients using the same keys.
+type TokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a TokenBucket allowing requestsPerSecond requests per second on
+// average, and up to burst requests at once. A burst below 1 is taken as 1. The rate must
+// be positive and finite.
+func NewTokenBucket(requestsPerSecond float64, burst int) (*TokenBucket, error) {
+	if !(requestsPerSecond > 0) || math.IsInf(requestsPerSecond, 1) {
+		return nil, fmt.Errorf("requests per second must be positive and finite, but got %v", requestsPerSecond)
+	}
+	b := &TokenBucket{rate: requestsPerSecond, burst: math.Max(float64(burst), 1)}
+	b.tokens = b.burst
+	b.last = time.Now()
+	return b, nil
+}
+
+// advance adds the tokens earned since the last call. The caller must hold mu.
+func (b *TokenBucket) advance(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// Wait takes a token, waiting for it if none is left. A request that would have to wait
+// beyond the deadline of ctx fails right away with context.DeadlineExceeded.
+func (b *TokenBucket) Wait(ctx context.Context, req *http.Request) (time.Duration, error) {
+	b.mu.Lock()
+	now := time.Now()
+	b.advance(now)
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if delay == 0 {
+		return 0, nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		b.cancel()
+		return 0, context.DeadlineExceeded
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		b.cancel()
+		return time.Since(now), ctx.Err()
+	}
+}
+
+// cancel gives back a token taken by a request that was not sent.
+func (b *TokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(time.Now())
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// ReadWriteLimiter applies separate budgets to requests reading and changing resources.
+// Either of them may be nil to leave those requests unlimited.
+type ReadWriteLimiter struct {
+	// Read limits GET requests.
+	Read RateLimiter
+	// Write limits POST, PUT, PATCH and DELETE requests.
+	Write RateLimiter
+}
+
+// Wait waits on the limiter of the kind of request.
+func (l *ReadWriteLimiter) Wait(ctx context.Context, req *http.Request) (time.Duration, error) {
+	limiter := l.Read
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		limiter = l.Write
+	}
+	if limiter == nil {
+		return 0, nil
+	}
+	return limiter.Wait(ctx, req)
+}
+
+// WithRateLimit limits the client to requestsPerSecond requests per second on average,
+// with bursts of up to burst requests.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) error {
+		bucket, err := NewTokenBucket(requestsPerSecond, burst)
+		if err != nil {
+			return err
+		}
+		c.rateLimiter = bucket
+		return nil
+	}
+}
+
+// WithRateLimiter sets the limiter pacing the requests of the client, for instance a
+// ReadWriteLimiter, or a TokenBucket shared with other clients.
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(c *Client) error {
+		if limiter == nil {
+			return fmt.Errorf("rate limiter cannot be nil")
+		}
+		c.rateLimiter = limiter
+		return nil
+	}
+}
+
+// waitRateLimit waits on the rate limiter, if any, and returns how long it waited.
+func (c *Client) waitRateLimit(ctx context.Context, req *http.Request) (time.Duration, error) {
+	if c.rateLimiter == nil {
+		return 0, nil
+	}
+	return c.rateLimiter.Wait(ctx, req)
+}
@@ -0,0 +1,143 @@
+package mopinion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTokenBucket(t *testing.T, requestsPerSecond float64, burst int) *TokenBucket {
+	bucket, err := NewTokenBucket(requestsPerSecond, burst)
+	if err != nil {
+		t.Fatalf("creating a token bucket should not return an error: %s", err)
+	}
+	return bucket
+}
+
+func TestTokenBucket(t *testing.T) {
+	bucket := newTokenBucket(t, 50, 2)
+	req, _ := http.NewRequest("GET", "/", nil)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := bucket.Wait(context.Background(), req); err != nil {
+			t.Fatalf("waiting should not return an error: %s", err)
+		}
+	}
+	// Two requests are sent at once, the next two 20ms apart.
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("requests beyond the burst should wait, but took %s", elapsed)
+	}
+}
+
+func TestTokenBucketCancel(t *testing.T) {
+	bucket := newTokenBucket(t, 1, 1)
+	req, _ := http.NewRequest("GET", "/", nil)
+	if _, err := bucket.Wait(context.Background(), req); err != nil {
+		t.Fatalf("waiting should not return an error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := bucket.Wait(ctx, req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded but got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("a wait beyond the deadline should fail right away, but took %s", elapsed)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := bucket.Wait(ctx, req); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled but got: %v", err)
+	}
+}
+
+func TestTokenBucketRate(t *testing.T) {
+	for _, rate := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		if _, err := NewTokenBucket(rate, 1); err == nil {
+			t.Errorf("a rate of %v should return an error", rate)
+		}
+	}
+}
+
+func TestReadWriteLimiter(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client, err := NewClient(NewBasicCredentialProvider("publickey", "privatekey"),
+		WithBaseURL(server.URL+"/"),
+		WithRateLimiter(&ReadWriteLimiter{Write: newTokenBucket(t, 20, 1)}))
+	if err != nil {
+		t.Fatalf("creating a client should not return an error: %s", err)
+	}
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"token":"token"}`)
+	})
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "Mopinion"}`)
+	})
+	mux.HandleFunc("/reports", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	for i := 0; i < 3; i++ {
+		_, resp, err := client.Account.Get(context.Background())
+		if err != nil {
+			t.Fatalf("account API should not return an error: %s", err)
+		}
+		if resp.RateLimitWait != 0 {
+			t.Errorf("reads should not be limited, but waited %s", resp.RateLimitWait)
+		}
+	}
+
+	var waited time.Duration
+	for i := 0; i < 3; i++ {
+		_, resp, err := client.Reports.Add(context.Background(), &Report{Name: "report name"})
+		if err != nil {
+			t.Fatalf("reports API should not return an error: %s", err)
+		}
+		waited += resp.RateLimitWait
+	}
+	// The second and third report wait 50ms each.
+	if waited < 80*time.Millisecond {
+		t.Errorf("writes should be limited, but waited %s", waited)
+	}
+}
+
+func TestRateLimitLogging(t *testing.T) {
+	client, mux, logger, teardown := newLoggingClient(t, WithRateLimit(20, 1))
+	defer teardown()
+
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "Mopinion"}`)
+	})
+
+	// The token request takes the only token, so the account request waits.
+	if _, _, err := client.Account.Get(context.Background()); err != nil {
+		t.Fatalf("account API should not return an error: %s", err)
+	}
+	if len(logger.entries) != 2 {
+		t.Fatalf("expected %d log entries but got: %d", 2, len(logger.entries))
+	}
+	if wait, ok := logger.entries[1].keysAndValues["rate_limit_wait"].(time.Duration); !ok || wait <= 0 {
+		t.Errorf("expected the rate limit wait to be logged, but got: %+v", logger.entries[1])
+	}
+}
+
+func TestRateLimitOptions(t *testing.T) {
+	provider := NewBasicCredentialProvider("publickey", "privatekey")
+	if _, err := NewClient(provider, WithRateLimit(0, 1)); err == nil {
+		t.Errorf("a rate of zero should return an error")
+	}
+	if _, err := NewClient(provider, WithRateLimiter(nil)); err == nil {
+		t.Errorf("a nil rate limiter should return an error")
+	}
+}
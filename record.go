@@ -0,0 +1,107 @@
+package mopinion
+
+// Record is a feedback item seen through a FieldSchema, to look up its values by key,
+// label or short label without scanning the fields. The same question may have different
+// keys across datasets, but usually keeps its label.
+//
+//	schema := mopinion.NewFieldSchema(fields.Data)
+//	record := schema.Record(feedback)
+//	if value, ok := record.GetByLabel("How satisfied are you?"); ok {
+//		score, err := value.AsScore()
+//		...
+//	}
+type Record struct {
+	Feedback FeedbackData
+
+	schema *FieldSchema
+	keys   map[string]int
+}
+
+// Record returns the record of a feedback item.
+func (s *FieldSchema) Record(feedback FeedbackData) *Record {
+	r := &Record{Feedback: feedback, schema: s, keys: make(map[string]int, len(feedback.Fields))}
+	for i, field := range feedback.Fields {
+		if _, ok := r.keys[field.Key]; !ok {
+			r.keys[field.Key] = i
+		}
+	}
+	return r
+}
+
+func (r *Record) value(field FieldData) (Value, bool) {
+	i, ok := r.keys[field.Key]
+	if !ok {
+		return Value{}, false
+	}
+	return Value{Field: r.Feedback.Fields[i], Schema: &field}, true
+}
+
+// Get returns the value of the field with the given key. Fields that are not in the
+// schema are found as well, without a schema.
+func (r *Record) Get(key string) (Value, bool) {
+	if field, ok := r.schema.Field(key); ok {
+		return r.value(field)
+	}
+	i, ok := r.keys[key]
+	if !ok {
+		return Value{}, false
+	}
+	return Value{Field: r.Feedback.Fields[i]}, true
+}
+
+// GetByLabel returns the value of a field with the given label in the schema. When several
+// fields have the label, e.g. in a report schema covering several datasets, the value of
+// the first one the feedback has is returned.
+func (r *Record) GetByLabel(label string) (Value, bool) {
+	return r.first(r.schema.labels[label])
+}
+
+// GetByShortLabel returns the value of a field with the given short label in the schema,
+// like GetByLabel.
+func (r *Record) GetByShortLabel(shortLabel string) (Value, bool) {
+	return r.first(r.schema.shortLabels[shortLabel])
+}
+
+// first returns the value of the first of the schema fields that the feedback has.
+func (r *Record) first(indexes []int) (Value, bool) {
+	for _, i := range indexes {
+		if value, ok := r.value(r.schema.fields[i]); ok {
+			return value, true
+		}
+	}
+	return Value{}, false
+}
+
+// Values returns the values of the fields in the schema order. Fields of the schema that
+// the feedback does not have are left out; see Missing.
+func (r *Record) Values() []Value {
+	var values []Value
+	for _, field := range r.schema.fields {
+		if value, ok := r.value(field); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Missing returns the fields of the schema that the feedback does not have.
+func (r *Record) Missing() []FieldData {
+	var missing []FieldData
+	for _, field := range r.schema.fields {
+		if _, ok := r.keys[field.Key]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
+// Unknown returns the fields of the feedback that are not in the schema.
+func (r *Record) Unknown() []FeedbackField {
+	var unknown []FeedbackField
+	for _, field := range r.Feedback.Fields {
+		if _, ok := r.schema.Field(field.Key); !ok {
+			unknown = append(unknown, field)
+		}
+	}
+	return unknown
+}
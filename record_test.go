@@ -0,0 +1,106 @@
+package mopinion
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecord(t *testing.T) {
+	schema := NewFieldSchema([]FieldData{
+		{Key: "1.RATING.a", Label: "How satisfied are you?", ShortLabel: "satisfaction", Type: FieldTypeRating, AnswerOptions: &AnswerOptions{Scale: 5}},
+		{Key: "2.TEXT.b", Label: "Comment", ShortLabel: "comment", Type: FieldTypeText},
+		{Key: "3.EMAIL.c", Label: "Email", ShortLabel: "email", Type: FieldTypeEmail},
+		{Key: "4.TEXT.d", Label: "Comment", ShortLabel: "other comment", Type: FieldTypeText},
+	})
+	record := schema.Record(FeedbackData{ID: 1, Fields: []FeedbackField{
+		{Key: "2.TEXT.b", Value: "fine"},
+		{Key: "1.RATING.a", Value: "4"},
+		{Key: "url", Value: "https://example.com"},
+	}})
+
+	value, ok := record.GetByLabel("How satisfied are you?")
+	if !ok {
+		t.Fatalf("expected a value by label")
+	}
+	if score, err := value.AsScore(); err != nil || score.Value != 4 {
+		t.Errorf("expected score 4 but got: %+v, %v", score, err)
+	}
+	if value, ok := record.GetByShortLabel("comment"); !ok || value.Field.Value != "fine" {
+		t.Errorf("expected comment by short label but got: %+v, %v", value, ok)
+	}
+	if value, ok := record.GetByLabel("Comment"); !ok || value.Schema.Key != "2.TEXT.b" {
+		t.Errorf("expected the field with a label that the feedback has but got: %+v, %v", value, ok)
+	}
+	if _, ok := record.Get("3.EMAIL.c"); ok {
+		t.Errorf("expected no value for a missing field")
+	}
+	if value, ok := record.Get("url"); !ok || value.Schema != nil {
+		t.Errorf("expected a value without schema for an unknown field but got: %+v, %v", value, ok)
+	}
+
+	var keys []string
+	for _, value := range record.Values() {
+		keys = append(keys, value.Field.Key)
+	}
+	if expected := []string{"1.RATING.a", "2.TEXT.b"}; !reflect.DeepEqual(expected, keys) {
+		t.Errorf("expected keys in schema order: %v but got: %v", expected, keys)
+	}
+
+	var missing []string
+	for _, field := range record.Missing() {
+		missing = append(missing, field.Key)
+	}
+	if expected := []string{"3.EMAIL.c", "4.TEXT.d"}; !reflect.DeepEqual(expected, missing) {
+		t.Errorf("expected missing fields: %v but got: %v", expected, missing)
+	}
+	if unknown := record.Unknown(); len(unknown) != 1 || unknown[0].Key != "url" {
+		t.Errorf("expected unknown field url but got: %+v", unknown)
+	}
+}
+
+func TestRecordAcrossDatasets(t *testing.T) {
+	// A report schema has the same question once for every dataset, each with its own key.
+	schema := NewFieldSchema([]FieldData{
+		{DatasetID: 1, Key: "1.NPS.a", Label: "Would you recommend us?", ShortLabel: "nps", Type: FieldTypeNps, AnswerOptions: &AnswerOptions{Scale: 10, StartAtZero: true}},
+		{DatasetID: 2, Key: "7.NPS.x", Label: "Would you recommend us?", ShortLabel: "nps", Type: FieldTypeNps, AnswerOptions: &AnswerOptions{Scale: 10, StartAtZero: true}},
+	})
+
+	tests := map[string]FeedbackData{
+		"1.NPS.a": {ID: 1, DatasetID: 1, Fields: []FeedbackField{{Key: "1.NPS.a", Value: 9}}},
+		"7.NPS.x": {ID: 2, DatasetID: 2, Fields: []FeedbackField{{Key: "7.NPS.x", Value: 3}}},
+	}
+	for key, feedback := range tests {
+		record := schema.Record(feedback)
+		if value, ok := record.GetByLabel("Would you recommend us?"); !ok || value.Schema.Key != key {
+			t.Errorf("%s: expected the value by label but got: %+v, %v", key, value, ok)
+		}
+		if value, ok := record.GetByShortLabel("nps"); !ok || value.Schema.Key != key {
+			t.Errorf("%s: expected the value by short label but got: %+v, %v", key, value, ok)
+		}
+	}
+
+	if _, ok := schema.Record(FeedbackData{ID: 3}).GetByLabel("Would you recommend us?"); ok {
+		t.Errorf("expected no value for feedback without the question")
+	}
+	if fields := schema.FieldsByLabel("Would you recommend us?"); len(fields) != 2 || fields[1].Key != "7.NPS.x" {
+		t.Errorf("expected both fields with the label but got: %+v", fields)
+	}
+	if field, ok := schema.FieldByShortLabel("nps"); !ok || field.Key != "1.NPS.a" {
+		t.Errorf("expected the first field with the short label but got: %+v, %v", field, ok)
+	}
+}
+
+func TestRecordDuplicateKeys(t *testing.T) {
+	schema := NewFieldSchema([]FieldData{
+		{Key: "nps", Label: "First"},
+		{Key: "nps", Label: "Second"},
+	})
+	if field, ok := schema.Field("nps"); !ok || field.Label != "First" {
+		t.Errorf("expected the first field with the key but got: %+v, %v", field, ok)
+	}
+
+	record := schema.Record(FeedbackData{Fields: []FeedbackField{{Key: "nps", Value: 9}, {Key: "nps", Value: 3}}})
+	if value, ok := record.Get("nps"); !ok || value.Field.Value != 9 || value.Schema.Label != "First" {
+		t.Errorf("expected the first value with the key but got: %+v, %v", value, ok)
+	}
+}
@@ -0,0 +1,145 @@
+package mopinion
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultMinBackoff  = 500 * time.Millisecond
+	defaultMaxBackoff  = 30 * time.Second
+)
+
+// RetryPolicy decides whether a failed request should be sent again.
+type RetryPolicy interface {
+	// Retry is called after the given attempt failed, starting from 1.
+	// It returns how long to wait before the next attempt and whether there should be one.
+	// resp is nil if the request did not get a response at all.
+	Retry(attempt int, req *http.Request, resp *Response, err error) (time.Duration, bool)
+}
+
+// BackoffRetryPolicy implements RetryPolicy with exponential backoff and jitter.
+// Only the errors reported by IsRetryable are retried.
+type BackoffRetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+
+	// MinBackoff is the delay before the first retry. It doubles on every following retry.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between two attempts, including the delay asked for
+	// by a Retry-After header.
+	MaxBackoff time.Duration
+
+	// RetryNonIdempotent allows POST requests to be retried. A failed POST may still
+	// have created the resource, so retrying it can result in duplicates.
+	RetryNonIdempotent bool
+}
+
+// NewBackoffRetryPolicy returns a BackoffRetryPolicy with sensible defaults.
+func NewBackoffRetryPolicy() *BackoffRetryPolicy {
+	return &BackoffRetryPolicy{
+		MaxAttempts: defaultMaxAttempts,
+		MinBackoff:  defaultMinBackoff,
+		MaxBackoff:  defaultMaxBackoff,
+	}
+}
+
+// Retry implements RetryPolicy.
+func (p *BackoffRetryPolicy) Retry(attempt int, req *http.Request, resp *Response, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || !IsRetryable(err) {
+		return 0, false
+	}
+	if req.Method == http.MethodPost && !p.RetryNonIdempotent {
+		return 0, false
+	}
+	if resp != nil {
+		if delay, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+				delay = p.MaxBackoff
+			}
+			return delay, true
+		}
+	}
+	return p.backoff(attempt), true
+}
+
+// backoff returns a random delay between the half and the whole of the exponential backoff.
+func (p *BackoffRetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.MinBackoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// retryAfter parses the value of a Retry-After header, either in seconds or as an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		delay := time.Until(t)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// IsRetryable reports whether the request failed with an error that may go away
+// when the request is sent again. These are the error codes the Mopinion API documents
+// as retryable (server error, failed to update and failed to create the resource),
+// HTTP statuses pointing at overloaded or unavailable servers, timeouts and broken
+// connections.
+func IsRetryable(err error) bool {
+	switch errorCode(err) {
+	case ErrorCodeServer, ErrorCodeFailedToUpdateResource, ErrorCodeFailedToCreateResource:
+		return true
+	}
+	if r := errorResponse(err); r != nil && r.Response != nil {
+		switch r.Response.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	return isConnectionError(err)
+}
+
+// isConnectionError reports whether the request timed out or its connection could not be
+// made or broke off. Other transport errors, such as a failed TLS handshake or an
+// unsupported URL scheme, would fail the same way again.
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		switch opErr.Op {
+		case "dial", "read", "write":
+			return true
+		}
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,213 @@
+package mopinion
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryServerError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/reports/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"status": 500, "error_code": 2, "title": "Server error"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id": 1, "name": "report name"}`)
+	})
+
+	client.RetryPolicy = &BackoffRetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	report, _, err := client.Reports.Get(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("reports API should not return an error: %s", err)
+	}
+	if report.ID != 1 {
+		t.Errorf("expected report id: %v but got: %v", 1, report.ID)
+	}
+	if calls != 3 {
+		t.Errorf("expected %d calls but got: %d", 3, calls)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/reports/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"status": 500, "error_code": 2, "title": "Server error"}`)
+	})
+
+	client.RetryPolicy = &BackoffRetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	_, _, err := client.Reports.Get(context.Background(), 1)
+	if _, ok := err.(*ServerError); !ok {
+		t.Errorf("err should be a ServerError but received error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected %d calls but got: %d", 2, calls)
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/reports", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"status": 500, "error_code": 16, "title": "Failed to create the resource"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id": 1, "name": "report name"}`)
+	})
+
+	policy := &BackoffRetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	client.RetryPolicy = policy
+	if _, _, err := client.Reports.Add(context.Background(), &Report{Name: "report name"}); err == nil {
+		t.Errorf("POST requests should not be retried by default")
+	}
+
+	calls = 0
+	policy.RetryNonIdempotent = true
+	report, _, err := client.Reports.Add(context.Background(), &Report{Name: "report name"})
+	if err != nil {
+		t.Fatalf("reports API should not return an error: %s", err)
+	}
+	if report.ID != 1 || calls != 2 {
+		t.Errorf("expected report id %v after %d calls but got: %v after %d calls", 1, 2, report.ID, calls)
+	}
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/reports/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"status": 404, "error_code": 8, "title": "Report not found"}`)
+	})
+
+	client.RetryPolicy = &BackoffRetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	if _, _, err := client.Reports.Get(context.Background(), 1); err == nil {
+		t.Errorf("reports API should return an error")
+	}
+	if calls != 1 {
+		t.Errorf("expected %d call but got: %d", 1, calls)
+	}
+}
+
+func TestRetryContextDeadline(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/reports/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	client.RetryPolicy = NewBackoffRetryPolicy()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	if _, _, err := client.Reports.Get(ctx, 1); err == nil {
+		t.Errorf("reports API should return an error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("client should give up without waiting, but waited %s", elapsed)
+	}
+	if calls != 1 {
+		t.Errorf("expected %d call but got: %d", 1, calls)
+	}
+}
+
+func TestIsRetryableNetworkErrors(t *testing.T) {
+	_, unsupported := http.Get("unknown://example.com")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening should not return an error: %s", err)
+	}
+	listener.Close()
+	_, refused := http.Get("http://" + listener.Addr().String())
+
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"unsupported scheme", unsupported, false},
+		{"connection refused", refused, true},
+		{"timeout", &url.Error{Op: "Get", URL: "/", Err: &net.DNSError{IsTimeout: true}}, true},
+		{"connection reset", &url.Error{Op: "Get", URL: "/", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, true},
+		{"connection closed", &url.Error{Op: "Get", URL: "/", Err: io.EOF}, true},
+		{"certificate", &url.Error{Op: "Get", URL: "/", Err: x509.UnknownAuthorityError{}}, false},
+	}
+	for _, test := range tests {
+		if retryable := IsRetryable(test.err); retryable != test.retryable {
+			t.Errorf("%s: expected retryable %v but got: %v for %v", test.name, test.retryable, retryable, test.err)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"invalid", 0, false},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+	}
+	for _, test := range tests {
+		delay, ok := retryAfter(test.value)
+		if delay != test.expected || ok != test.ok {
+			t.Errorf("retryAfter(%q): expected %v, %v but got: %v, %v", test.value, test.expected, test.ok, delay, ok)
+		}
+	}
+}
+
+func TestRetryAfterMaxBackoff(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/reports/1", nil)
+	resp := &Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {"120"}}}}
+	err := &ErrorResponse{Response: resp.Response}
+
+	policy := &BackoffRetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Second}
+	if delay, ok := policy.Retry(1, req, resp, err); !ok || delay != time.Second {
+		t.Errorf("expected the Retry-After delay capped at %v but got: %v, %v", time.Second, delay, ok)
+	}
+	policy.MaxBackoff = 0
+	if delay, ok := policy.Retry(1, req, resp, err); !ok || delay != 120*time.Second {
+		t.Errorf("expected the Retry-After delay %v but got: %v, %v", 120*time.Second, delay, ok)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := &BackoffRetryPolicy{MaxAttempts: 10, MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt := 1; attempt < 10; attempt++ {
+		delay := policy.backoff(attempt)
+		if delay < 50*time.Millisecond || delay > time.Second {
+			t.Errorf("backoff for attempt %d out of range: %s", attempt, delay)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package mopinion
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CredentialNotifier is implemented by credential providers that know when their keys change,
+// such as WatchedFileCredentialProvider. The client reads the keys again after a notification.
+type CredentialNotifier interface {
+	// Changed returns a channel receiving a value whenever the keys may have changed.
+	Changed() <-chan struct{}
+}
+
+// WithCredentialRefreshInterval makes the client read the keys from the credential provider
+// again at the given interval. If the keys have changed, a new token is retrieved with them.
+// Regardless of this option, the keys are read again whenever the API rejects them.
+func WithCredentialRefreshInterval(interval time.Duration) Option {
+	return func(c *Client) error {
+		if interval <= 0 {
+			return fmt.Errorf("credential refresh interval must be positive, but got %s", interval)
+		}
+		c.credentialRefreshInterval = interval
+		return nil
+	}
+}
+
+// keysDue reports whether the keys should be read from the credential provider again.
+func (c *Client) keysDue() bool {
+	if notifier, ok := c.credentialProvider.(CredentialNotifier); ok {
+		select {
+		case <-notifier.Changed():
+			c.tokenMu.Lock()
+			c.keysStale = true
+			c.tokenMu.Unlock()
+		default:
+		}
+	}
+
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	if c.keysStale {
+		return true
+	}
+	return c.credentialRefreshInterval > 0 && time.Since(c.keysLoadedAt) >= c.credentialRefreshInterval
+}
+
+// reloadKeys reads the keys from the credential provider and reports whether they differ
+// from the current ones. The new keys are not used until a token is retrieved with them.
+func (c *Client) reloadKeys() (string, string, bool, error) {
+	publicKey, privateKey, err := c.credentialProvider.Keys()
+	if err != nil {
+		return "", "", false, fmt.Errorf("read keys: %s", err)
+	}
+
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	c.keysLoadedAt = time.Now()
+	c.keysStale = false
+	changed := publicKey != c.publicKey || privateKey != c.privateKey
+	return publicKey, privateKey, changed, nil
+}
+
+// keepKeys records a failed reload of keys that were only due, so the current keys stay
+// in use until the next reload is due, instead of the keys being read on every request.
+func (c *Client) keepKeys(err error) {
+	if c.logger != nil {
+		c.logger.Error("reload keys", "error", err)
+	}
+	c.tokenMu.Lock()
+	c.keysLoadedAt = time.Now()
+	c.keysStale = false
+	c.tokenMu.Unlock()
+}
+
+// renewToken retrieves a new token if the current one is rejected or missing, or if the
+// keys have been rotated. The keys are read again when the API rejects them or they are due.
+// When keys that were only due cannot be read, the current token is kept.
+func (c *Client) renewToken(ctx context.Context, current *Token, rejected bool) (*Token, error) {
+	publicKey, privateKey := c.keys()
+	reloaded, changed := false, false
+	if (current != nil && rejected) || c.keysDue() {
+		var err error
+		if publicKey, privateKey, changed, err = c.reloadKeys(); err != nil {
+			if current != nil && !rejected {
+				c.keepKeys(err)
+				return current, nil
+			}
+			return nil, err
+		}
+		reloaded = true
+	}
+	if current != nil && !rejected && !changed {
+		return current, nil
+	}
+
+	token, err := c.fetchToken(ctx, publicKey, privateKey)
+	if err != nil && !reloaded && isCredentialError(err) {
+		// The keys may have been rotated since they were last read.
+		publicKey, privateKey, changed, reloadErr := c.reloadKeys()
+		if reloadErr == nil && changed {
+			token, err = c.fetchToken(ctx, publicKey, privateKey)
+		}
+	}
+	return token, err
+}
+
+// fetchToken retrieves a token with the given keys, then makes both the keys
+// and the token current at once.
+func (c *Client) fetchToken(ctx context.Context, publicKey, privateKey string) (*Token, error) {
+	tokenService, ok := c.Token.(*TokenService)
+	if !ok {
+		// Other implementations of TokenInterface read the keys from the client.
+		c.setKeys(publicKey, privateKey)
+		token, _, err := c.Token.Get(ctx)
+		return token, err
+	}
+
+	token, _, err := tokenService.get(ctx, publicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	c.tokenMu.Lock()
+	c.publicKey, c.privateKey = publicKey, privateKey
+	c.token = token
+	c.tokenMu.Unlock()
+	c.saveToken(token)
+	return token, nil
+}
+
+func (c *Client) setKeys(publicKey, privateKey string) {
+	c.tokenMu.Lock()
+	c.publicKey, c.privateKey = publicKey, privateKey
+	c.tokenMu.Unlock()
+}
@@ -0,0 +1,275 @@
+package mopinion
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// rotatingCredentialProvider returns whatever keys are set last, or the error set last.
+type rotatingCredentialProvider struct {
+	mu         sync.Mutex
+	publicKey  string
+	privateKey string
+	err        error
+	calls      int
+}
+
+func (p *rotatingCredentialProvider) Keys() (string, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.err != nil {
+		return "", "", p.err
+	}
+	return p.publicKey, p.privateKey, nil
+}
+
+func (p *rotatingCredentialProvider) fail(err error) {
+	p.mu.Lock()
+	p.err = err
+	p.mu.Unlock()
+}
+
+func (p *rotatingCredentialProvider) keysCalls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func (p *rotatingCredentialProvider) set(publicKey, privateKey string) {
+	p.mu.Lock()
+	p.publicKey, p.privateKey = publicKey, privateKey
+	p.mu.Unlock()
+}
+
+// rotationServer only accepts the keys set last and the tokens issued for them.
+type rotationServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	publicKey  string
+	privateKey string
+	tokens     map[string]string // token by public key
+	tokenCalls int
+}
+
+func newRotationServer(publicKey, privateKey string) *rotationServer {
+	s := &rotationServer{tokens: map[string]string{}}
+	s.rotate(publicKey, privateKey)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.tokenCalls++
+		expected := "Basic " + base64.StdEncoding.EncodeToString([]byte(s.publicKey+":"+s.privateKey))
+		if r.Header.Get("Authorization") != expected {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"status": 401, "error_code": 18, "title": "The credentials you provided are not valid"}`)
+			return
+		}
+		token := fmt.Sprintf("token-%s-%d", s.publicKey, s.tokenCalls)
+		s.tokens[s.publicKey] = token
+		fmt.Fprintf(w, `{"token":%q}`, token)
+	})
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		body, _ := ioutil.ReadAll(r.Body)
+		header, _ := base64.StdEncoding.DecodeString(r.Header.Get("x-auth-token"))
+		publicKey := strings.SplitN(string(header), ":", 2)[0]
+		token, ok := s.tokens[publicKey]
+		if publicKey != s.publicKey || !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"status": 401, "error_code": 3, "title": "Public key not found"}`)
+			return
+		}
+		client := &Client{}
+		if r.Header.Get("x-auth-token") != client.makeToken(&Token{Token: token, publicKey: publicKey}, r.URL.Path, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"status": 401, "error_code": 4, "title": "Invalid token"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"name": %q}`, publicKey)
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *rotationServer) rotate(publicKey, privateKey string) {
+	s.mu.Lock()
+	s.publicKey, s.privateKey = publicKey, privateKey
+	s.tokens = map[string]string{}
+	s.mu.Unlock()
+}
+
+func (s *rotationServer) calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokenCalls
+}
+
+func assertAccountName(t *testing.T, client *Client, expected string) {
+	t.Helper()
+	account, _, err := client.Account.Get(context.Background())
+	if err != nil {
+		t.Fatalf("account API should not return an error: %s", err)
+	}
+	if account.Name != expected {
+		t.Errorf("expected account name: %v but got: %v", expected, account.Name)
+	}
+}
+
+func TestCredentialRotationOnAuthenticationFailure(t *testing.T) {
+	server := newRotationServer("public1", "private1")
+	defer server.Close()
+
+	provider := &rotatingCredentialProvider{publicKey: "public1", privateKey: "private1"}
+	client, err := NewClient(provider, WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient should not return an error: %s", err)
+	}
+	assertAccountName(t, client, "public1")
+
+	// The keys are rotated while the client holds a token for the old ones.
+	server.rotate("public2", "private2")
+	provider.set("public2", "private2")
+	assertAccountName(t, client, "public2")
+
+	if publicKey, privateKey := client.keys(); publicKey != "public2" || privateKey != "private2" {
+		t.Errorf("expected keys: public2, private2 but got: %v, %v", publicKey, privateKey)
+	}
+}
+
+func TestCredentialRotationOnTokenFailure(t *testing.T) {
+	server := newRotationServer("public1", "private1")
+	defer server.Close()
+
+	provider := &rotatingCredentialProvider{publicKey: "public1", privateKey: "private1"}
+	client, err := NewClient(provider, WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient should not return an error: %s", err)
+	}
+
+	// The keys are rotated before the client gets its first token.
+	server.rotate("public1", "private2")
+	provider.set("public1", "private2")
+	assertAccountName(t, client, "public1")
+	if calls := server.calls(); calls != 2 {
+		t.Errorf("expected token API to be called %d times but got: %d", 2, calls)
+	}
+}
+
+func TestCredentialRefreshInterval(t *testing.T) {
+	server := newRotationServer("public1", "private1")
+	defer server.Close()
+
+	provider := &rotatingCredentialProvider{publicKey: "public1", privateKey: "private1"}
+	client, err := NewClient(provider, WithBaseURL(server.URL+"/"), WithCredentialRefreshInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewClient should not return an error: %s", err)
+	}
+	assertAccountName(t, client, "public1")
+
+	// Unchanged keys do not cause a new token.
+	time.Sleep(2 * time.Millisecond)
+	assertAccountName(t, client, "public1")
+	if calls := server.calls(); calls != 1 {
+		t.Errorf("expected token API to be called %d time but got: %d", 1, calls)
+	}
+
+	// Rotated keys are picked up without a failing request.
+	provider.set("public2", "private2")
+	server.rotate("public2", "private2")
+	time.Sleep(2 * time.Millisecond)
+	assertAccountName(t, client, "public2")
+	if calls := server.calls(); calls != 2 {
+		t.Errorf("expected token API to be called %d times but got: %d", 2, calls)
+	}
+}
+
+func TestWatchedFileCredentialProvider(t *testing.T) {
+	path, cleanup := writeCredentialsFile(t, "[default]\npublic_key = public1\nprivate_key = private1\n", 0600)
+	defer cleanup()
+
+	provider, err := NewWatchedFileCredentialProvider(path, "", time.Millisecond)
+	if err != nil {
+		t.Fatalf("provider should not return an error: %s", err)
+	}
+	defer provider.Close()
+
+	server := newRotationServer("public1", "private1")
+	defer server.Close()
+	client, err := NewClient(provider, WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient should not return an error: %s", err)
+	}
+	assertAccountName(t, client, "public1")
+
+	content := "[default]\npublic_key = public2\nprivate_key = private2\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("write credentials file: %s", err)
+	}
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("provider should report the modification")
+	case <-provider.Changed():
+	}
+	assertKeys(t, provider, "public2", "private2")
+
+	// The client reads the keys again after the next notification.
+	server.rotate("public2", "private2")
+	select {
+	case provider.changed <- struct{}{}:
+	default: // The watcher has already sent one.
+	}
+	assertAccountName(t, client, "public2")
+	if calls := server.calls(); calls != 2 {
+		t.Errorf("expected token API to be called %d times but got: %d", 2, calls)
+	}
+}
+
+func TestCredentialRefreshFailure(t *testing.T) {
+	server := newRotationServer("public1", "private1")
+	defer server.Close()
+
+	provider := &rotatingCredentialProvider{publicKey: "public1", privateKey: "private1"}
+	logger := &recordingLogger{}
+	client, err := NewClient(provider, WithBaseURL(server.URL+"/"), WithLogger(logger),
+		WithCredentialRefreshInterval(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewClient should not return an error: %s", err)
+	}
+	assertAccountName(t, client, "public1")
+
+	// The keys can't be read for a while, but the token is still good.
+	provider.fail(fmt.Errorf("file temporarily unavailable"))
+	time.Sleep(60 * time.Millisecond)
+	calls := provider.keysCalls()
+	assertAccountName(t, client, "public1")
+	assertAccountName(t, client, "public1")
+	if reads := provider.keysCalls() - calls; reads != 1 {
+		t.Errorf("expected the keys to be read %d time until the next refresh but got: %d", 1, reads)
+	}
+	var logged bool
+	for _, entry := range logger.entries {
+		logged = logged || (entry.msg == "reload keys" && entry.level == "error")
+	}
+	if !logged {
+		t.Errorf("expected the failed reload to be logged")
+	}
+
+	// Without a usable token, the error is returned.
+	server.rotate("public1", "private1")
+	if _, _, err := client.Account.Get(context.Background()); err == nil || !strings.Contains(err.Error(), "file temporarily unavailable") {
+		t.Errorf("expected the error reading the keys but got: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package mopinion
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// AuthTokenHeader is the header carrying the signature of a request.
+const AuthTokenHeader = "x-auth-token"
+
+// Reasons for a signature to be rejected by Verifier.Verify. The returned errors
+// wrap one of them, so they can be checked with errors.Is.
+var (
+	ErrSignatureMissing   = errors.New("mopinion: x-auth-token header is missing")
+	ErrSignatureMalformed = errors.New("mopinion: x-auth-token header is malformed")
+	ErrUnknownPublicKey   = errors.New("mopinion: public key is unknown")
+	ErrSignatureMismatch  = errors.New("mopinion: signature does not match")
+)
+
+// Signer signs requests for the Mopinion API. The x-auth-token header holds the
+// base64 encoding of the public key and the hex encoded HMAC-SHA256 of the request
+// path and body, keyed with a token: base64(publicKey:hex(HMAC(token, path|body))).
+type Signer struct {
+	PublicKey string
+	// Token is a token issued for PublicKey.
+	Token string
+}
+
+// Signature returns the x-auth-token header of a request with the given path and body.
+func (s Signer) Signature(path string, body []byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(s.PublicKey + ":" + s.mac(path, body)))
+}
+
+func (s Signer) mac(path string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(s.Token))
+	mac.Write([]byte(path + "|"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign sets the x-auth-token header of the request. The body is read and restored,
+// and GetBody is set, so the request can be signed and sent again.
+func (s Signer) Sign(req *http.Request) error {
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	req.Header.Set(AuthTokenHeader, s.Signature(signedPath(req), body))
+	return nil
+}
+
+// Verifier checks the x-auth-token header of requests, for servers and proxies
+// receiving signed traffic.
+type Verifier struct {
+	tokens func(publicKey string) ([]string, bool)
+}
+
+// NewVerifier returns a Verifier. The tokens function returns the tokens issued for a
+// public key, and whether the public key is known at all.
+func NewVerifier(tokens func(publicKey string) ([]string, bool)) *Verifier {
+	return &Verifier{tokens: tokens}
+}
+
+// Verify checks the signature of the request against every token of its public key,
+// in constant time, and returns the public key. The body is read and restored.
+func (v *Verifier) Verify(req *http.Request) (string, error) {
+	header := req.Header.Get(AuthTokenHeader)
+	if header == "" {
+		return "", ErrSignatureMissing
+	}
+	decoded, err := base64.StdEncoding.DecodeString(header)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrSignatureMalformed, err)
+	}
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", fmt.Errorf("%w: expected public key and signature separated by a colon", ErrSignatureMalformed)
+	}
+	publicKey, signature := parts[0], parts[1]
+
+	tokens, ok := v.tokens(publicKey)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownPublicKey, publicKey)
+	}
+	body, err := readBody(req)
+	if err != nil {
+		return "", err
+	}
+	path := signedPath(req)
+	for _, token := range tokens {
+		expected := Signer{PublicKey: publicKey, Token: token}.mac(path, body)
+		if hmac.Equal([]byte(expected), []byte(signature)) {
+			return publicKey, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no token of %q signed %s", ErrSignatureMismatch, publicKey, path)
+}
+
+// signedPath returns the path that is signed, which always has a leading slash.
+func signedPath(req *http.Request) string {
+	// Relative paths may omit leading slash.
+	if !strings.HasPrefix(req.URL.Path, "/") {
+		return "/" + req.URL.Path
+	}
+	return req.URL.Path
+}
+
+// readBody reads the body of the request and restores it to its original state.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %s", err)
+	}
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
@@ -0,0 +1,75 @@
+package mopinion
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestSignerAndVerifier(t *testing.T) {
+	verifier := NewVerifier(func(publicKey string) ([]string, bool) {
+		if publicKey != "publickey" {
+			return nil, false
+		}
+		return []string{"old", "token"}, true
+	})
+	newRequest := func(signer *Signer) *http.Request {
+		req, _ := http.NewRequest("POST", "http://localhost/reports", bytes.NewBufferString(`{"name":"Website"}`))
+		if signer != nil {
+			if err := signer.Sign(req); err != nil {
+				t.Fatalf("signing should not return an error: %s", err)
+			}
+		}
+		return req
+	}
+
+	req := newRequest(&Signer{PublicKey: "publickey", Token: "token"})
+	publicKey, err := verifier.Verify(req)
+	if err != nil || publicKey != "publickey" {
+		t.Errorf("expected the request to be verified but got: %q, %v", publicKey, err)
+	}
+	if body, _ := ioutil.ReadAll(req.Body); string(body) != `{"name":"Website"}` {
+		t.Errorf("expected the body to be restored but got: %s", body)
+	}
+
+	tests := map[string]struct {
+		header   string
+		expected error
+	}{
+		"missing":    {"", ErrSignatureMissing},
+		"not base64": {"%%%", ErrSignatureMalformed},
+		"no colon":   {base64.StdEncoding.EncodeToString([]byte("publickey")), ErrSignatureMalformed},
+		"unknown":    {Signer{PublicKey: "otherkey", Token: "token"}.Signature("/reports", nil), ErrUnknownPublicKey},
+		"wrong token": {
+			Signer{PublicKey: "publickey", Token: "forged"}.Signature("/reports", []byte(`{"name":"Website"}`)),
+			ErrSignatureMismatch,
+		},
+		"wrong body": {
+			Signer{PublicKey: "publickey", Token: "token"}.Signature("/reports", []byte(`{"name":"App"}`)),
+			ErrSignatureMismatch,
+		},
+	}
+	for name, test := range tests {
+		req := newRequest(nil)
+		if test.header != "" {
+			req.Header.Set(AuthTokenHeader, test.header)
+		}
+		if _, err := verifier.Verify(req); !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected error: %v but got: %v", name, test.expected, err)
+		}
+	}
+}
+
+func TestSignerRelativePath(t *testing.T) {
+	signer := Signer{PublicKey: "publickey", Token: "token"}
+	req, _ := http.NewRequest("GET", "account", nil)
+	if err := signer.Sign(req); err != nil {
+		t.Fatalf("signing should not return an error: %s", err)
+	}
+	if expected := signer.Signature("/account", nil); req.Header.Get(AuthTokenHeader) != expected {
+		t.Errorf("expected header: %s but got: %s", expected, req.Header.Get(AuthTokenHeader))
+	}
+}
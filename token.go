@@ -18,13 +18,27 @@ type TokenService struct {
 
 // Get returns a token by passing the keys with basic authentication.
 func (s *TokenService) Get(ctx context.Context) (*Token, *Response, error) {
+	publicKey, privateKey := s.client.keys()
+	token, resp, err := s.get(ctx, publicKey, privateKey)
+	if err != nil {
+		return nil, resp, err
+	}
+	// We store the token to be used in the future.
+	s.client.SetToken(token)
+	s.client.saveToken(token)
+
+	return token, resp, nil
+}
+
+// get returns a token for the given keys without storing it.
+func (s *TokenService) get(ctx context.Context, publicKey, privateKey string) (*Token, *Response, error) {
 	req, err := s.client.NewRequest("GET", "token", nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// Basic authentication https://en.wikipedia.org/wiki/Basic_access_authentication
-	authValue := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", s.client.publicKey, s.client.privateKey)))
+	authValue := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", publicKey, privateKey)))
 	req.Header.Add("Authorization", fmt.Sprintf("Basic %s", authValue))
 
 	token := new(Token)
@@ -32,8 +46,7 @@ func (s *TokenService) Get(ctx context.Context) (*Token, *Response, error) {
 	if err != nil {
 		return nil, resp, err
 	}
-	// We store the token to be used in the future.
-	s.client.token = token
+	token.publicKey = publicKey
 
 	return token, resp, nil
 }
@@ -0,0 +1,179 @@
+package mopinion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// TokenStore keeps tokens, keyed by public key, so they can be reused by later
+// clients, e.g. after a process restart. Several accounts can share one store.
+type TokenStore interface {
+	// Load returns the token stored for the public key, or nil if there is none.
+	Load(publicKey string) (*Token, error)
+
+	// Save stores the token for the public key, replacing any previous one.
+	Save(publicKey string, token *Token) error
+}
+
+// WithTokenStore makes the client load its token from the store when it is created,
+// and save every token it retrieves to the store. A token that cannot be loaded is
+// retrieved from the API instead. Failures to load or save are logged if a Logger is set.
+func WithTokenStore(store TokenStore) Option {
+	return func(c *Client) error {
+		if store == nil {
+			return fmt.Errorf("token store cannot be nil")
+		}
+		c.tokenStore = store
+		return nil
+	}
+}
+
+// loadToken sets the token stored for the current public key, if any.
+func (c *Client) loadToken() {
+	publicKey, _ := c.keys()
+	token, err := c.tokenStore.Load(publicKey)
+	if err != nil && c.logger != nil {
+		c.logger.Error("load token", "error", err)
+	}
+	if err != nil || token == nil || token.Token == "" {
+		return
+	}
+	token.publicKey = publicKey
+	c.SetToken(token)
+}
+
+// saveToken saves the token to the token store, if there is one.
+func (c *Client) saveToken(token *Token) {
+	if c.tokenStore == nil {
+		return
+	}
+	publicKey := token.publicKey
+	if publicKey == "" {
+		publicKey, _ = c.keys()
+	}
+	if err := c.tokenStore.Save(publicKey, token); err != nil && c.logger != nil {
+		c.logger.Error("save token", "error", err)
+	}
+}
+
+// MemoryTokenStore implements TokenStore in memory. It is useful to share
+// tokens between clients living in the same process.
+type MemoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: make(map[string]string)}
+}
+
+// Load implements TokenStore.
+func (m *MemoryTokenStore) Load(publicKey string) (*Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.tokens[publicKey]
+	if !ok {
+		return nil, nil
+	}
+	return &Token{Token: token}, nil
+}
+
+// Save implements TokenStore.
+func (m *MemoryTokenStore) Save(publicKey string, token *Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[publicKey] = token.Token
+	return nil
+}
+
+// FileTokenStore implements TokenStore with a JSON file only readable and writable by
+// its owner. The file is replaced atomically on every save, so concurrent processes
+// never read a partially written file.
+type FileTokenStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileTokenStore returns a FileTokenStore for the given file. The file and its
+// directory are created on the first save.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+type storedToken struct {
+	Token string `json:"token"`
+}
+
+func (f *FileTokenStore) read() (map[string]storedToken, error) {
+	tokens := make(map[string]storedToken)
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("parse token file %s: %s", f.path, err)
+	}
+	return tokens, nil
+}
+
+// Load implements TokenStore.
+func (f *FileTokenStore) Load(publicKey string) (*Token, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	token, ok := tokens[publicKey]
+	if !ok {
+		return nil, nil
+	}
+	return &Token{Token: token.Token}, nil
+}
+
+// Save implements TokenStore.
+func (f *FileTokenStore) Save(publicKey string, token *Token) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens, err := f.read()
+	if err != nil {
+		// Start over rather than keeping a broken file forever.
+		tokens = make(map[string]storedToken)
+	}
+	tokens[publicKey] = storedToken{Token: token.Token}
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(f.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, filepath.Base(f.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	// TempFile creates the file with mode 0600 already, but be explicit about it.
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
@@ -0,0 +1,118 @@
+package mopinion
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryTokenStore(t *testing.T) {
+	tokenCalls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenCalls++
+		fmt.Fprint(w, `{"token":"token"}`)
+	})
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "account name"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	store := NewMemoryTokenStore()
+	for i := 0; i < 3; i++ {
+		client, err := NewClient(NewBasicCredentialProvider("publickey", "privatekey"),
+			WithBaseURL(server.URL+"/"), WithTokenStore(store))
+		if err != nil {
+			t.Fatalf("NewClient should not return an error: %s", err)
+		}
+		if _, _, err := client.Account.Get(context.Background()); err != nil {
+			t.Fatalf("account API should not return an error: %s", err)
+		}
+	}
+	if tokenCalls != 1 {
+		t.Errorf("expected token API to be called %d time but got: %d", 1, tokenCalls)
+	}
+
+	token, err := store.Load("otherkey")
+	if err != nil || token != nil {
+		t.Errorf("expected no token for another public key but got: %v, %v", token, err)
+	}
+}
+
+func TestFileTokenStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mopinion")
+	if err != nil {
+		t.Fatalf("create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cache", "tokens.json")
+
+	store := NewFileTokenStore(path)
+	if token, err := store.Load("publickey"); err != nil || token != nil {
+		t.Errorf("expected no token before the first save but got: %v, %v", token, err)
+	}
+	if err := store.Save("publickey", &Token{Token: "token1"}); err != nil {
+		t.Fatalf("save should not return an error: %s", err)
+	}
+	if err := store.Save("otherkey", &Token{Token: "token2"}); err != nil {
+		t.Fatalf("save should not return an error: %s", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("token file should exist: %s", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("expected file mode: %v but got: %v", os.FileMode(0600), mode)
+	}
+
+	// A new store, as in a new process, reads the saved tokens.
+	store = NewFileTokenStore(path)
+	for publicKey, expected := range map[string]string{"publickey": "token1", "otherkey": "token2"} {
+		token, err := store.Load(publicKey)
+		if err != nil || token == nil || token.Token != expected {
+			t.Errorf("expected token: %v for %v but got: %v, %v", expected, publicKey, token, err)
+		}
+	}
+
+	client, err := NewClient(NewBasicCredentialProvider("publickey", "privatekey"), WithTokenStore(store))
+	if err != nil {
+		t.Fatalf("NewClient should not return an error: %s", err)
+	}
+	if token := client.getToken(); token == nil || token.Token != "token1" {
+		t.Errorf("expected the client to start with token: %v but got: %v", "token1", token)
+	}
+}
+
+func TestFileTokenStoreCorrupt(t *testing.T) {
+	path, cleanup := writeCredentialsFile(t, "not json", 0600)
+	defer cleanup()
+
+	store := NewFileTokenStore(path)
+	if _, err := store.Load("publickey"); err == nil {
+		t.Errorf("load should return an error for a corrupt file")
+	}
+
+	// The client logs the error, gets a token from the API instead and overwrites the file.
+	logger := &recordingLogger{}
+	client, err := NewClient(NewBasicCredentialProvider("publickey", "privatekey"), WithTokenStore(store), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewClient should not return an error: %s", err)
+	}
+	if token := client.getToken(); token != nil {
+		t.Errorf("expected no token but got: %v", token)
+	}
+	if len(logger.entries) != 1 || logger.entries[0].msg != "load token" || logger.entries[0].keysAndValues["error"] == nil {
+		t.Errorf("expected the load error to be logged but got: %+v", logger.entries)
+	}
+	client.saveToken(&Token{Token: "token"})
+	if token, err := store.Load("publickey"); err != nil || token == nil || token.Token != "token" {
+		t.Errorf("expected token: %v but got: %v, %v", "token", token, err)
+	}
+}
@@ -0,0 +1,343 @@
+package mopinion
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Field types of FieldData.Type that Value checks the typed accessors against.
+// Values of other types are converted by their shape only.
+const (
+	FieldTypeRating   = "rating"
+	FieldTypeNps      = "nps"
+	FieldTypeCes      = "ces"
+	FieldTypeGcr      = "gcr"
+	FieldTypeThumbs   = "thumbs"
+	FieldTypeCheckbox = "checkbox"
+	FieldTypeCategory = "category"
+	FieldTypeRadio    = "radio"
+	FieldTypeSelect   = "select"
+	FieldTypeText     = "text"
+	FieldTypeTextarea = "textarea"
+	FieldTypeInput    = "input"
+	FieldTypeEmail    = "email"
+	FieldTypeURL      = "url"
+	FieldTypeDate     = "date"
+)
+
+type valueKind int
+
+const (
+	unknownKind valueKind = iota
+	numberKind
+	boolKind
+	textKind
+	timeKind
+)
+
+var fieldKinds = map[string]valueKind{
+	FieldTypeRating:   numberKind,
+	FieldTypeNps:      numberKind,
+	FieldTypeCes:      numberKind,
+	FieldTypeThumbs:   boolKind,
+	FieldTypeGcr:      textKind,
+	FieldTypeCheckbox: textKind,
+	FieldTypeCategory: textKind,
+	FieldTypeRadio:    textKind,
+	FieldTypeSelect:   textKind,
+	FieldTypeText:     textKind,
+	FieldTypeTextarea: textKind,
+	FieldTypeInput:    textKind,
+	FieldTypeEmail:    textKind,
+	FieldTypeURL:      textKind,
+	FieldTypeDate:     timeKind,
+}
+
+// FieldSchema holds the fields of a dataset or report, as returned by FieldsService,
+// to interpret the values of feedback.
+//
+//	fields, _, err := client.Fields.GetByDataset(ctx, datasetID)
+//	...
+//	schema := mopinion.NewFieldSchema(fields.Data)
+//	for _, field := range feedback.Fields {
+//		score, err := schema.Value(field).AsScore()
+//		...
+//	}
+type FieldSchema struct {
+	fields      []FieldData
+	keys        map[string]int
+	labels      map[string][]int
+	shortLabels map[string][]int
+}
+
+// NewFieldSchema returns a schema of the given fields. When several fields have the same
+// key, the first one is used, as for labels.
+func NewFieldSchema(fields []FieldData) *FieldSchema {
+	s := &FieldSchema{
+		fields:      fields,
+		keys:        make(map[string]int, len(fields)),
+		labels:      make(map[string][]int, len(fields)),
+		shortLabels: make(map[string][]int, len(fields)),
+	}
+	for i, field := range fields {
+		if _, ok := s.keys[field.Key]; !ok {
+			s.keys[field.Key] = i
+		}
+		// Labels need not be unique: the same question has a different key in every
+		// dataset of a report. All fields with a label are kept, in schema order.
+		if field.Label != "" {
+			s.labels[field.Label] = append(s.labels[field.Label], i)
+		}
+		if field.ShortLabel != "" {
+			s.shortLabels[field.ShortLabel] = append(s.shortLabels[field.ShortLabel], i)
+		}
+	}
+	return s
+}
+
+// Fields returns the fields of the schema in their original order.
+func (s *FieldSchema) Fields() []FieldData {
+	return s.fields
+}
+
+// Field returns the field with the given key.
+func (s *FieldSchema) Field(key string) (FieldData, bool) {
+	i, ok := s.keys[key]
+	if !ok {
+		return FieldData{}, false
+	}
+	return s.fields[i], true
+}
+
+// FieldByLabel returns the first field with the given label.
+func (s *FieldSchema) FieldByLabel(label string) (FieldData, bool) {
+	return s.first(s.labels[label])
+}
+
+// FieldByShortLabel returns the first field with the given short label.
+func (s *FieldSchema) FieldByShortLabel(shortLabel string) (FieldData, bool) {
+	return s.first(s.shortLabels[shortLabel])
+}
+
+// FieldsByLabel returns all fields with the given label, in schema order.
+func (s *FieldSchema) FieldsByLabel(label string) []FieldData {
+	return s.all(s.labels[label])
+}
+
+// FieldsByShortLabel returns all fields with the given short label, in schema order.
+func (s *FieldSchema) FieldsByShortLabel(shortLabel string) []FieldData {
+	return s.all(s.shortLabels[shortLabel])
+}
+
+func (s *FieldSchema) first(indexes []int) (FieldData, bool) {
+	if len(indexes) == 0 {
+		return FieldData{}, false
+	}
+	return s.fields[indexes[0]], true
+}
+
+func (s *FieldSchema) all(indexes []int) []FieldData {
+	fields := make([]FieldData, len(indexes))
+	for i, index := range indexes {
+		fields[i] = s.fields[index]
+	}
+	return fields
+}
+
+// Value returns the value of a feedback field along with its schema, if the field is known.
+func (s *FieldSchema) Value(field FeedbackField) Value {
+	v := Value{Field: field}
+	if data, ok := s.Field(field.Key); ok {
+		v.Schema = &data
+	}
+	return v
+}
+
+// Value is the value of a feedback field, with typed accessors. When Schema is set, the
+// accessors check the value against the declared field type and answer options.
+type Value struct {
+	Field  FeedbackField
+	Schema *FieldData
+}
+
+// ValueError is returned by the accessors of Value when the value cannot be converted.
+type ValueError struct {
+	Key    string
+	Type   string
+	Value  interface{}
+	As     string
+	Reason string
+}
+
+func (e *ValueError) Error() string {
+	field := fmt.Sprintf("field %q", e.Key)
+	if e.Type != "" {
+		field = fmt.Sprintf("%s of type %q", field, e.Type)
+	}
+	return fmt.Sprintf("%s: cannot use %#v as %s: %s", field, e.Value, e.As, e.Reason)
+}
+
+func (v Value) errorf(as string, format string, args ...interface{}) error {
+	e := &ValueError{Key: v.Field.Key, Value: v.Field.Value, As: as, Reason: fmt.Sprintf(format, args...)}
+	if v.Schema != nil {
+		e.Type = v.Schema.Type
+	}
+	return e
+}
+
+// check returns an error if the declared type of the field is not of one of the given kinds.
+func (v Value) check(as string, kinds ...valueKind) error {
+	if v.Schema == nil {
+		return nil
+	}
+	kind := fieldKinds[v.Schema.Type]
+	if kind == unknownKind {
+		return nil
+	}
+	for _, k := range kinds {
+		if kind == k {
+			return nil
+		}
+	}
+	return v.errorf(as, "the field type does not hold a %s", as)
+}
+
+// IsNull reports whether the field has no value.
+func (v Value) IsNull() bool {
+	return v.Field.Value == nil
+}
+
+// AsInt returns the value as a whole number. Numbers sent as text are accepted.
+func (v Value) AsInt() (int, error) {
+	if err := v.check("int", numberKind); err != nil {
+		return 0, err
+	}
+	n, ok := fieldNumber(v.Field.Value)
+	if !ok {
+		return 0, v.errorf("int", "not a number")
+	}
+	if n != math.Trunc(n) {
+		return 0, v.errorf("int", "not a whole number")
+	}
+	return int(n), nil
+}
+
+// Score is a value on the scale of a rating, NPS or CES field.
+type Score struct {
+	Value int
+	// Min and Max are the bounds of the scale, following AnswerOptions.
+	Min int
+	Max int
+}
+
+// Normalized returns the score scaled to the range from 0 to 1.
+func (s Score) Normalized() float64 {
+	if s.Max == s.Min {
+		return 0
+	}
+	return float64(s.Value-s.Min) / float64(s.Max-s.Min)
+}
+
+// AsScore returns the value on the scale of the field. It needs the schema with the
+// answer options of the field, and fails if the value is outside the scale.
+func (v Value) AsScore() (Score, error) {
+	n, err := v.AsInt()
+	if err != nil {
+		return Score{}, err
+	}
+	if v.Schema == nil || v.Schema.AnswerOptions == nil || v.Schema.AnswerOptions.Scale <= 0 {
+		return Score{}, v.errorf("score", "the field has no scale")
+	}
+	score := Score{Value: n, Min: 1, Max: v.Schema.AnswerOptions.Scale}
+	if v.Schema.AnswerOptions.StartAtZero {
+		score.Min = 0
+	}
+	if n < score.Min || n > score.Max {
+		return Score{}, v.errorf("score", "outside the scale from %d to %d", score.Min, score.Max)
+	}
+	return score, nil
+}
+
+// AsStrings returns the answers of the field, such as the options checked in a checkbox
+// field. A single value is returned as one answer and null as none. If the schema lists
+// the answer values of the field, every answer must be one of them.
+func (v Value) AsStrings() ([]string, error) {
+	if err := v.check("strings", textKind); err != nil {
+		return nil, err
+	}
+	values, ok := v.Field.Value.([]interface{})
+	if !ok {
+		if v.Field.Value == nil {
+			return nil, nil
+		}
+		values = []interface{}{v.Field.Value}
+	}
+
+	answers := make([]string, 0, len(values))
+	for _, value := range values {
+		s, ok := fieldString(value)
+		if !ok {
+			return nil, v.errorf("strings", "%#v is not text", value)
+		}
+		if v.Schema != nil && len(v.Schema.AnswerValues) > 0 && !containsString(v.Schema.AnswerValues, s) {
+			return nil, v.errorf("strings", "%q is not one of the answer values", s)
+		}
+		answers = append(answers, s)
+	}
+	return answers, nil
+}
+
+// AsBool returns the value of a yes or no question, such as a thumbs field.
+// Besides booleans, 1 and 0 and the texts yes, no, true, false, up and down are accepted.
+func (v Value) AsBool() (bool, error) {
+	if err := v.check("bool", boolKind); err != nil {
+		return false, err
+	}
+	switch value := v.Field.Value.(type) {
+	case bool:
+		return value, nil
+	case float64:
+		if value == 0 || value == 1 {
+			return value == 1, nil
+		}
+	case string:
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "1", "yes", "true", "up", "thumbs up", "thumbsup":
+			return true, nil
+		case "0", "no", "false", "down", "thumbs down", "thumbsdown":
+			return false, nil
+		}
+	}
+	return false, v.errorf("bool", "not a yes or no answer")
+}
+
+// AsTime returns the value as a time, in the given location unless the value has a time
+// zone. A nil location means UTC; pass Client.DateLocation to match the decoded dates.
+func (v Value) AsTime(location *time.Location) (time.Time, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	if err := v.check("time", timeKind); err != nil {
+		return time.Time{}, err
+	}
+	s, ok := v.Field.Value.(string)
+	if !ok {
+		return time.Time{}, v.errorf("time", "not text")
+	}
+	t, err := parseTime(s, location)
+	if err != nil {
+		return time.Time{}, v.errorf("time", "%s", err)
+	}
+	return t, nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package mopinion
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var valuesSchema = NewFieldSchema([]FieldData{
+	{Key: "123.RATING.abc", Type: FieldTypeRating, AnswerOptions: &AnswerOptions{Scale: 5}},
+	{Key: "124.NPS.def", Type: FieldTypeNps, AnswerOptions: &AnswerOptions{Scale: 10, StartAtZero: true}},
+	{Key: "125.THUMBS.ghi", Type: FieldTypeThumbs},
+	{Key: "126.CHECKBOX.jkl", Type: FieldTypeCheckbox, AnswerValues: []string{"delivery", "price", "service"}},
+	{Key: "127.DATE.mno", Type: FieldTypeDate},
+	{Key: "128.CUSTOM.pqr", Type: "custom"},
+})
+
+func feedbackValue(t *testing.T, key, value string) Value {
+	field := FeedbackField{Key: key}
+	if err := json.Unmarshal([]byte(value), &field.Value); err != nil {
+		t.Fatalf("unmarshaling should not return an error: %s", err)
+	}
+	return valuesSchema.Value(field)
+}
+
+func TestValueAsScore(t *testing.T) {
+	score, err := feedbackValue(t, "123.RATING.abc", `"4"`).AsScore()
+	if err != nil {
+		t.Fatalf("AsScore should not return an error: %s", err)
+	}
+	if expected := (Score{Value: 4, Min: 1, Max: 5}); score != expected || score.Normalized() != 0.75 {
+		t.Errorf("expected score: %+v but got: %+v", expected, score)
+	}
+
+	score, err = feedbackValue(t, "124.NPS.def", `0`).AsScore()
+	if err != nil || score != (Score{Value: 0, Min: 0, Max: 10}) {
+		t.Errorf("expected a zero NPS but got: %+v, %v", score, err)
+	}
+
+	if _, err := feedbackValue(t, "123.RATING.abc", `0`).AsScore(); err == nil {
+		t.Errorf("a score outside the scale should return an error")
+	}
+	if _, err := feedbackValue(t, "128.CUSTOM.pqr", `3`).AsScore(); err == nil {
+		t.Errorf("a score without a scale should return an error")
+	}
+}
+
+func TestValueAsInt(t *testing.T) {
+	if n, err := feedbackValue(t, "128.CUSTOM.pqr", `"12"`).AsInt(); err != nil || n != 12 {
+		t.Errorf("expected 12 but got: %v, %v", n, err)
+	}
+	if _, err := feedbackValue(t, "123.RATING.abc", `3.5`).AsInt(); err == nil {
+		t.Errorf("a fraction should return an error")
+	}
+
+	_, err := feedbackValue(t, "125.THUMBS.ghi", `1`).AsInt()
+	var valueErr *ValueError
+	if !errors.As(err, &valueErr) || valueErr.Type != FieldTypeThumbs || valueErr.As != "int" {
+		t.Errorf("a thumbs field should not be an int, but got: %v", err)
+	}
+}
+
+func TestValueAsBool(t *testing.T) {
+	tests := map[string]bool{`"up"`: true, `"Thumbs down"`: false, `true`: true, `0`: false}
+	for value, expected := range tests {
+		if b, err := feedbackValue(t, "125.THUMBS.ghi", value).AsBool(); err != nil || b != expected {
+			t.Errorf("%s: expected %v but got: %v, %v", value, expected, b, err)
+		}
+	}
+	if _, err := feedbackValue(t, "125.THUMBS.ghi", `"maybe"`).AsBool(); err == nil {
+		t.Errorf("an unknown answer should return an error")
+	}
+}
+
+func TestValueAsStrings(t *testing.T) {
+	answers, err := feedbackValue(t, "126.CHECKBOX.jkl", `["delivery", "price"]`).AsStrings()
+	if expected := []string{"delivery", "price"}; err != nil || !reflect.DeepEqual(expected, answers) {
+		t.Errorf("expected answers: %v but got: %v, %v", expected, answers, err)
+	}
+	if answers, err := feedbackValue(t, "126.CHECKBOX.jkl", `null`).AsStrings(); err != nil || answers != nil {
+		t.Errorf("expected no answers but got: %v, %v", answers, err)
+	}
+	if _, err := feedbackValue(t, "126.CHECKBOX.jkl", `["delivery", "quality"]`).AsStrings(); err == nil {
+		t.Errorf("an answer that is not an answer value should return an error")
+	}
+	if _, err := feedbackValue(t, "123.RATING.abc", `4`).AsStrings(); err == nil {
+		t.Errorf("a rating field should not be strings")
+	}
+	if answers, err := feedbackValue(t, "unknown", `"free text"`).AsStrings(); err != nil || !reflect.DeepEqual([]string{"free text"}, answers) {
+		t.Errorf("expected a single answer but got: %v, %v", answers, err)
+	}
+}
+
+func TestValueAsTime(t *testing.T) {
+	tm, err := feedbackValue(t, "127.DATE.mno", `"2019-05-02 13:04:05"`).AsTime(nil)
+	if expected := time.Date(2019, 5, 2, 13, 4, 5, 0, time.UTC); err != nil || !tm.Equal(expected) {
+		t.Errorf("expected time: %v but got: %v, %v", expected, tm, err)
+	}
+	tm, err = feedbackValue(t, "127.DATE.mno", `"2019-05-02 13:04:05"`).AsTime(time.FixedZone("CET", 60*60))
+	if expected := time.Date(2019, 5, 2, 12, 4, 5, 0, time.UTC); err != nil || !tm.Equal(expected) {
+		t.Errorf("expected time: %v but got: %v, %v", expected, tm, err)
+	}
+	if _, err := feedbackValue(t, "127.DATE.mno", `"02/05/2019"`).AsTime(nil); err == nil {
+		t.Errorf("an unknown date format should return an error")
+	}
+	if _, err := feedbackValue(t, "123.RATING.abc", `"2019-05-02"`).AsTime(nil); err == nil {
+		t.Errorf("a rating field should not be a time")
+	}
+}